@@ -0,0 +1,371 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/launch/launch"
+	"github.com/ok-chain/okchain/x/token"
+	"github.com/spf13/cobra"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+const (
+	flagManifest     = "manifest"
+	flagTemplate     = "template"
+	flagProfile      = "profile"
+	flagGenTxDir     = "gentx-dir"
+	flagOutput       = "output"
+	flagChainID      = "chain-id"
+	flagGenesisTime  = "genesis-time"
+	flagNow          = "now"
+	flagMaintain     = "maintain-conf"
+	flagPeersFile    = "peers-file"
+	flagPairsFile    = "pairs-file"
+	flagAllocReport  = "allocation-report"
+	flagAudit        = "conversion-audit"
+	flagConfigFile   = "config-fragment"
+	flagSeeds        = "seeds"
+	flagAccountOrder = "account-order"
+	flagMaxShare     = "max-validator-share"
+	flagMinHaltSet   = "min-halt-validators"
+	flagMinStartSet  = "min-start-validators"
+)
+
+// launchConfig holds the input and output locations of a genesis build
+type launchConfig struct {
+	manifestFile    string
+	genesisTemplate string
+	profiles        []string
+	genTxPath       string
+	genesisFile     string
+	peersFile       string
+	pairsFile       string
+	allocReport     string
+	auditFile       string
+	configFile      string
+	seeds           []string
+
+	chainID     string
+	genesisTime string
+	now         string // the genesis time must be after it when set
+	maintain    string // backend maintain.conf with the genesis time
+
+	accountOrder []string
+
+	// voting power concentration thresholds
+	maxShare    string
+	minHaltSet  int
+	minStartSet int
+}
+
+// register the flags shared by every command that builds a genesis
+func addBuildFlags(cmd *cobra.Command, cfg *launchConfig) {
+	cmd.Flags().StringVar(&cfg.manifestFile, flagManifest, defaultManifest, "launch manifest listing the allocation sources")
+	cmd.Flags().StringVar(&cfg.genesisTemplate, flagTemplate, defaultGenesisTemplate, "genesis template with the module params")
+	cmd.Flags().StringSliceVar(&cfg.profiles, flagProfile, nil, "params profiles overlaid on the template in order, a file or a name in "+defaultProfilesDir)
+	cmd.Flags().StringVar(&cfg.genTxPath, flagGenTxDir, defaultGenTxPath, "directory holding the gentx files")
+	cmd.Flags().StringVar(&cfg.chainID, flagChainID, "", "chain id of the new network (default: manifest chain_id, then "+defaultChainID+")")
+	cmd.Flags().StringVar(&cfg.genesisTime, flagGenesisTime, "", "genesis time, RFC3339 (default: manifest genesis_time, then "+defaultTimeGenesisString+")")
+	cmd.Flags().StringVar(&cfg.now, flagNow, "", "check the genesis time is after this time (RFC3339)")
+
+	cmd.Flags().StringSliceVar(&cfg.accountOrder, flagAccountOrder, launch.DefaultAccountOrder(),
+		"account numbers order, by "+launch.OrderCaptain+", "+launch.OrderSource+" and "+launch.OrderAddress)
+
+	thresholds := launch.DefaultPowerThresholds()
+	cmd.Flags().StringVar(&cfg.maxShare, flagMaxShare, thresholds.MaxShare.String(), "warn when a validator holds more of the bonded stake")
+	cmd.Flags().IntVar(&cfg.minHaltSet, flagMinHaltSet, thresholds.MinHaltSet, "warn when fewer validators hold more than 1/3 of the stake")
+	cmd.Flags().IntVar(&cfg.minStartSet, flagMinStartSet, thresholds.MinStartSet, "warn when fewer validators hold more than 2/3 of the stake")
+}
+
+func buildCmd() *cobra.Command {
+	var cfg launchConfig
+	cmd := &cobra.Command{
+		Use:   "build",
+		Short: "Build the genesis file from the allocations, the template and the gentxs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// check the seeds before spending time on the genesis
+			var seeds []launch.Peer
+			for _, s := range cfg.seeds {
+				seed, err := launch.ParsePeer(s)
+				if err != nil {
+					return err
+				}
+				seeds = append(seeds, seed)
+			}
+
+			b, genesisDoc, err := generateGenesis(cfg)
+			if err != nil {
+				return err
+			}
+			if err := launch.WriteGenesisDoc(b.Codec(), genesisDoc, cfg.genesisFile); err != nil {
+				return err
+			}
+			if err := writeMaintainConf(cfg.maintain, genesisDoc.GenesisTime); err != nil {
+				return err
+			}
+			if err := writeTradingPairs(cfg.pairsFile, b.TradingPairs()); err != nil {
+				return err
+			}
+			if err := writeConversionAudit(cfg.auditFile, b.Allocations()); err != nil {
+				return err
+			}
+			if cfg.allocReport != "" {
+				if err := writeAllocationReport(cfg.allocReport, b.Allocations()); err != nil {
+					return err
+				}
+			}
+			return writePeers(cfg, b.PersistentPeers(), seeds)
+		},
+	}
+	addBuildFlags(cmd, &cfg)
+	cmd.Flags().StringVarP(&cfg.genesisFile, flagOutput, "o", defaultGenesisFile, "where to write the genesis file")
+	cmd.Flags().StringVar(&cfg.peersFile, flagPeersFile, defaultPeersFile, "where to write the gentx peers, one per line")
+	cmd.Flags().StringVar(&cfg.configFile, flagConfigFile, defaultConfigFile, "where to write the config.toml p2p section")
+	cmd.Flags().StringVar(&cfg.pairsFile, flagPairsFile, defaultPairsFile, "where to write the trading pairs of the tokens file, if any")
+	cmd.Flags().StringVar(&cfg.auditFile, flagAudit, defaultAuditFile, "where to write the addresses converted from hex or pubkeys")
+	cmd.Flags().StringVar(&cfg.allocReport, flagAllocReport, "", "where to write every allocation with its source file and line (.csv, .md or text)")
+	cmd.Flags().StringSliceVar(&cfg.seeds, flagSeeds, nil, "seed nodes (nodeid@ip:port) of the config.toml p2p section")
+	cmd.Flags().StringVar(&cfg.maintain, flagMaintain, defaultMaintainFile, "where to write the backend maintain.conf")
+	return cmd
+}
+
+func validateCmd() *cobra.Command {
+	var cfg launchConfig
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Run every genesis check without writing the genesis file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, genesisDoc, err := generateGenesis(cfg)
+			if err != nil {
+				return err
+			}
+			if cfg.maintain != "" {
+				if err := launch.CheckMaintainConf(cfg.maintain, genesisDoc.GenesisTime); err != nil {
+					return err
+				}
+			}
+			fmt.Println("-----------")
+			fmt.Println("genesis is valid")
+			return nil
+		},
+	}
+	addBuildFlags(cmd, &cfg)
+	cmd.Flags().StringVar(&cfg.maintain, flagMaintain, "", "also check the genesis time of this backend maintain.conf")
+	return cmd
+}
+
+// write the backend maintain.conf and read it back as okchaind would
+func writeMaintainConf(file string, genesisTime time.Time) error {
+	if err := launch.WriteMaintainConf(launch.MaintainConf(genesisTime), file); err != nil {
+		return err
+	}
+	return launch.CheckMaintainConf(file, genesisTime)
+}
+
+// list the addresses converted to bech32, next to their original form.
+// Without conversions the audit only holds its header, which replaces the
+// audit of an earlier build.
+func writeConversionAudit(file string, allocs []launch.Allocation) error {
+	var buf bytes.Buffer
+	if err := launch.WriteConversionAudit(&buf, allocs); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), 0644)
+}
+
+// write the allocations report, its format follows the file extension
+func writeAllocationReport(file string, allocs []launch.Allocation) error {
+	return writeReport(file, launch.AllocationReport(allocs))
+}
+
+// write report tables in the format of the file extension: csv, markdown or text
+func writeReport(file string, tables []launch.Table) error {
+	format := launch.FormatText
+	switch filepath.Ext(file) {
+	case ".csv":
+		format = launch.FormatCSV
+	case ".md":
+		format = launch.FormatMarkdown
+	}
+	var buf bytes.Buffer
+	if err := launch.WriteTables(&buf, tables, format); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, buf.Bytes(), 0644)
+}
+
+// the token module has no genesis state for the trading pairs,
+// they are written apart to be listed once the chain runs
+func writeTradingPairs(file string, pairs []token.TokenPair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	bz, err := json.MarshalIndent(pairs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(bz, '\n'), 0644)
+}
+
+// write the persistent peers list and the config.toml fragment
+func writePeers(cfg launchConfig, peers, seeds []launch.Peer) error {
+	var buf bytes.Buffer
+	if err := launch.WritePeers(&buf, peers); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(cfg.peersFile, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	buf.Reset()
+	if err := launch.WriteConfigFragment(&buf, peers, seeds); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(cfg.configFile, buf.Bytes(), 0644)
+}
+
+// generateGenesis loads all the inputs and composes the genesis doc
+func generateGenesis(cfg launchConfig) (*launch.Builder, *tmtypes.GenesisDoc, error) {
+	manifest, err := launch.LoadManifest(cfg.manifestFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// flags first, then the manifest, then the defaults
+	chainID := firstNonEmpty(cfg.chainID, manifest.ChainID, defaultChainID)
+	if err := launch.CheckChainID(chainID); err != nil {
+		return nil, nil, err
+	}
+	genesisTimeString := firstNonEmpty(cfg.genesisTime, manifest.GenesisTime, defaultTimeGenesisString)
+	genesisTime, err := time.Parse(time.RFC3339, genesisTimeString)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid genesis time %q: %v", genesisTimeString, err)
+	}
+	if cfg.now != "" {
+		now, err := time.Parse(time.RFC3339, cfg.now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s %q: %v", flagNow, cfg.now, err)
+		}
+		if err := launch.CheckGenesisTime(genesisTime, now); err != nil {
+			return nil, nil, err
+		}
+	}
+	fmt.Println("chain id    ", chainID)
+	fmt.Println("genesis time", genesisTime.UTC().Format(time.RFC3339))
+
+	b := launch.NewBuilder(chainID, genesisTime)
+	maxShare, err := sdk.NewDecFromStr(cfg.maxShare)
+	if err != nil || maxShare.IsNegative() || maxShare.GT(sdk.OneDec()) {
+		return nil, nil, fmt.Errorf("invalid %s %q: expected a decimal between 0 and 1", flagMaxShare, cfg.maxShare)
+	}
+	b.PowerThresholds = launch.PowerThresholds{
+		MaxShare:    maxShare,
+		MinHaltSet:  cfg.minHaltSet,
+		MinStartSet: cfg.minStartSet,
+	}
+	if err := launch.CheckAccountOrder(cfg.accountOrder); err != nil {
+		return nil, nil, err
+	}
+	b.AccountOrder = cfg.accountOrder
+	for _, name := range cfg.profiles {
+		profile, err := launch.LoadProfile(profileFile(name))
+		if err != nil {
+			return nil, nil, err
+		}
+		fmt.Println("params profile", profile.File)
+		b.Profiles = append(b.Profiles, profile)
+	}
+
+	// every input must use the chain bech32 prefixes
+	files := []string{cfg.genesisTemplate}
+	for _, src := range manifest.Sources {
+		files = append(files, src.File)
+	}
+	genTxFiles, err := filepath.Glob(filepath.Join(cfg.genTxPath, "*.json"))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := launch.CheckPrefixes(append(files, genTxFiles...)); err != nil {
+		return nil, nil, err
+	}
+
+	// for each source, accumulate the contributors file.
+	for _, src := range manifest.Sources {
+		if err := b.AddSource(src); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	fmt.Println("-----------")
+	if err := launch.WriteSourceSummaries(os.Stdout, b.SourceSummaries()); err != nil {
+		return nil, nil, err
+	}
+
+	// check totals
+	if err := b.CheckSources(); err != nil {
+		return nil, nil, err
+	}
+	if n := launch.Conversions(b.Allocations()); n > 0 {
+		fmt.Println(n, "address(es) converted to bech32 from hex or pubkeys")
+	}
+
+	if manifest.Tokens != "" {
+		if err := b.AddTokensFile(manifest.Tokens); err != nil {
+			return nil, nil, err
+		}
+		fmt.Println("-----------")
+		fmt.Println("tokens file", manifest.Tokens, "with", len(b.TradingPairs()), "trading pair(s)")
+	}
+
+	if err := b.AddGenTxs(cfg.genTxPath); err != nil {
+		return nil, nil, err
+	}
+
+	fmt.Println("-----------")
+	fmt.Println("TOTAL gen txs", len(b.GenTxs()))
+
+	genesisDoc, err := b.GenesisDoc(cfg.genesisTemplate)
+	if reports := b.GenTxReports(); len(reports) > 0 {
+		fmt.Println("-----------")
+		if err := launch.WriteGenTxReports(os.Stdout, reports); err != nil {
+			return nil, nil, err
+		}
+	}
+	if report := b.PowerReport(); report != nil {
+		fmt.Println("-----------")
+		if err := launch.WritePowerReport(os.Stdout, report); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, genesisDoc, nil
+}
+
+// a profile is a file or the name of one in the profiles directory
+func profileFile(name string) string {
+	if strings.ContainsAny(name, `/\`) || filepath.Ext(name) != "" {
+		return name
+	}
+	return filepath.Join(defaultProfilesDir, name+".yaml")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/launch/launch"
+	"github.com/ok-chain/okchain/app"
+)
+
+// the shipped manifest, allocation files and template must agree on the
+// okb supply; the gentxs are left out as they carry their own checks
+func TestBuildShippedInputs(t *testing.T) {
+	genTxPath, err := ioutil.TempDir("", "gentx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(genTxPath)
+
+	thresholds := launch.DefaultPowerThresholds()
+	cfg := launchConfig{
+		manifestFile:    defaultManifest,
+		genesisTemplate: defaultGenesisTemplate,
+		genTxPath:       genTxPath,
+		accountOrder:    launch.DefaultAccountOrder(),
+		maxShare:        thresholds.MaxShare.String(),
+		minHaltSet:      thresholds.MinHaltSet,
+		minStartSet:     thresholds.MinStartSet,
+	}
+	for _, profiles := range [][]string{nil, {"mainnet"}, {"testnet"}, {"devnet"}} {
+		cfg.profiles = profiles
+		b, genesisDoc, err := generateGenesis(cfg)
+		if err != nil {
+			t.Fatalf("profiles %v: %v", profiles, err)
+		}
+		var state app.GenesisState
+		if err := b.Codec().UnmarshalJSON(genesisDoc.AppState, &state); err != nil {
+			t.Fatal(err)
+		}
+		total := launch.SumAllocations(b.Allocations())
+		for _, tok := range state.Token.Info {
+			if tok.Symbol == "okb" && !sdk.NewDec(tok.TotalSupply).Equal(total) {
+				t.Errorf("profiles %v: okb supply %d, allocations %v", profiles, tok.TotalSupply, total)
+			}
+		}
+	}
+}
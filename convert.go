@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/launch/launch"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagFrom  = "from"
+	flagTo    = "to"
+	flagWrite = "write"
+)
+
+func convertPrefixCmd() *cobra.Command {
+	var from, to string
+	var write bool
+	cmd := &cobra.Command{
+		Use:   "convert-prefix <file>...",
+		Short: "Re-encode the bech32 addresses and pubkeys of files with another prefix",
+		Long: `Re-encode every bech32 string using the --from main prefix, or one of its
+pub, valoper, valoperpub, valcons and valconspub variants, with the --to prefix.
+The converted document is printed unless --write rewrites the files in place.
+
+Gentx signatures cover the addresses, so a converted gentx has to be signed again.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !write && len(args) > 1 {
+				return fmt.Errorf("converting several files requires --%s", flagWrite)
+			}
+			for _, file := range args {
+				bz, err := ioutil.ReadFile(file)
+				if err != nil {
+					return err
+				}
+				out, n, err := launch.ConvertPrefix(bz, from, to)
+				if err != nil {
+					return fmt.Errorf("%s: %v", file, err)
+				}
+				fmt.Fprintf(os.Stderr, "%s: %d bech32 string(s) converted from %s to %s\n", file, n, from, to)
+				if n > 0 && bytes.Contains(bz, []byte("auth/StdTx")) {
+					fmt.Fprintf(os.Stderr, "%s: WARNING the signatures no longer verify, sign the gentx again\n", file)
+				}
+
+				if !write {
+					_, err = os.Stdout.Write(out)
+					return err
+				}
+				if err := ioutil.WriteFile(file, out, 0644); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, flagFrom, "cosmos", "main prefix to convert from")
+	cmd.Flags().StringVar(&to, flagTo, sdk.Bech32MainPrefix, "main prefix to convert to")
+	cmd.Flags().BoolVarP(&write, flagWrite, "w", false, "rewrite the files in place")
+	return cmd
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cosmos/launch/launch"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/go-amino"
+)
+
+const flagJSON = "json"
+
+func diffCmd() *cobra.Command {
+	var template string
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "diff <old-genesis-file> [new-genesis-file]",
+		Short: "Print the differences between two genesis files",
+		Long: `Print the differences between two genesis files: accounts, module params,
+gentxs by validator address and consensus params. With a single file, it is compared to the template.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				args = []string{template, args[0]}
+			}
+			cdc := amino.NewCodec()
+			oldDoc, oldState, err := launch.LoadGenesis(cdc, args[0])
+			if err != nil {
+				return err
+			}
+			newDoc, newState, err := launch.LoadGenesis(cdc, args[1])
+			if err != nil {
+				return err
+			}
+
+			d, err := launch.DiffGenesis(oldDoc, newDoc, oldState, newState)
+			if err != nil {
+				return err
+			}
+			if asJSON {
+				bz, err := json.MarshalIndent(d, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(bz))
+				return nil
+			}
+			if d.Empty() {
+				fmt.Println("no differences")
+				return nil
+			}
+			return launch.WriteGenesisDiff(os.Stdout, d)
+		},
+	}
+	cmd.Flags().StringVar(&template, flagTemplate, defaultGenesisTemplate, "genesis template to compare a single file to")
+	cmd.Flags().BoolVar(&asJSON, flagJSON, false, "print the differences as json")
+	return cmd
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cosmos/launch/launch"
+	"github.com/spf13/cobra"
+)
+
+func hashCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "hash [genesis-file]",
+		Short: "Print the SHA-256 of the canonical form of a genesis file",
+		Long: `Print the SHA-256 of the canonical form of a genesis file.
+The genesis written by build is canonical already, so this is also its sha256sum.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genesisFile := defaultGenesisFile
+			if len(args) == 1 {
+				genesisFile = args[0]
+			}
+			hash, err := launch.GenesisHash(genesisFile)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%x  %s\n", hash, genesisFile)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/cosmos/launch/launch"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/go-amino"
+)
+
+const (
+	flagFormat = "format"
+	flagTop    = "top"
+)
+
+func inspectCmd() *cobra.Command {
+	var format, output string
+	var top int
+	cmd := &cobra.Command{
+		Use:   "inspect [genesis-file]",
+		Short: "Print a report of a genesis file",
+		Long: `Print a report of a genesis file: supply per denom, top holders, vesting and
+its unlock calendar, validators and module params, as text, markdown or csv.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			genesisFile := defaultGenesisFile
+			if len(args) == 1 {
+				genesisFile = args[0]
+			}
+			genesisDoc, genesisState, err := launch.LoadGenesis(amino.NewCodec(), genesisFile)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			if output != "" {
+				f, err := os.Create(output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			return launch.WriteTables(w, launch.GenesisReport(genesisDoc, genesisState, top), format)
+		},
+	}
+	cmd.Flags().StringVar(&format, flagFormat, launch.FormatText, "report format: text, markdown or csv")
+	cmd.Flags().IntVar(&top, flagTop, 20, "number of top holders per denom, 0 for all")
+	cmd.Flags().StringVarP(&output, flagOutput, "o", "", "where to write the report instead of stdout")
+	return cmd
+}
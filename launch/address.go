@@ -0,0 +1,108 @@
+package launch
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+// forms of the addresses of the allocation files
+const (
+	AddressBech32 = "bech32"    // okchain1...
+	AddressHex    = "hex"       // the 20 bytes in hex
+	AddressEth    = "0x"        // an ethereum style address, refused
+	AddressPubKey = "secp256k1" // a compressed secp256k1 pubkey in hex, 33 bytes
+)
+
+// ParseAddress reads an allocation address given as bech32, as the hex of
+// its 20 bytes or as the hex of the compressed secp256k1 pubkey it belongs
+// to. It returns the form the address was given in.
+// 0x addresses are refused: an ethereum address is derived from another
+// hash of the key than the okchain one, taking its bytes as an account would
+// send the allocation to an address nobody holds the key of. The all-zero
+// address is refused whatever its form.
+func ParseAddress(address string) (sdk.AccAddress, string, error) {
+	addr, form, err := parseAddress(address)
+	if err == nil && isZero(addr) {
+		return nil, form, errors.New("the zero address can't hold an allocation")
+	}
+	return addr, form, err
+}
+
+func parseAddress(address string) (sdk.AccAddress, string, error) {
+	switch {
+	case strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X"):
+		return nil, AddressEth, errors.New("0x addresses look like ethereum ones, which don't map to okchain accounts, " +
+			"give the bech32 address or the compressed pubkey")
+	case isHex(address) && len(address) == 2*sdk.AddrLen:
+		bz, _ := hex.DecodeString(address)
+		return sdk.AccAddress(bz), AddressHex, nil
+	case isHex(address) && len(address) == 2*secp256k1.PubKeySecp256k1Size:
+		bz, _ := hex.DecodeString(address)
+		if bz[0] != 0x02 && bz[0] != 0x03 {
+			return nil, AddressPubKey, errors.New("not a compressed secp256k1 pubkey, expected 02 or 03 first")
+		}
+		if _, err := btcec.ParsePubKey(bz, btcec.S256()); err != nil {
+			return nil, AddressPubKey, err
+		}
+		var pub secp256k1.PubKeySecp256k1
+		copy(pub[:], bz)
+		return sdk.AccAddress(pub.Address()), AddressPubKey, nil
+	default:
+		addr, err := fromBech32(address)
+		return addr, AddressBech32, err
+	}
+}
+
+func isZero(bz []byte) bool {
+	for _, b := range bz {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteConversionAudit lists, as csv, every allocation address that wasn't
+// given as bech32 with the form and the value it was given in
+func WriteConversionAudit(w io.Writer, allocs []Allocation) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"source", "form", "original", "address"})
+	for _, alloc := range allocs {
+		if alloc.Original == "" {
+			continue
+		}
+		cw.Write([]string{alloc.Position(), alloc.AddressForm, alloc.Original, alloc.Address.String()})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Conversions counts the allocation addresses that weren't given as bech32
+func Conversions(allocs []Allocation) int {
+	n := 0
+	for _, alloc := range allocs {
+		if alloc.Original != "" {
+			n++
+		}
+	}
+	return n
+}
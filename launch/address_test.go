@@ -0,0 +1,89 @@
+package launch
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+func TestParseAddress(t *testing.T) {
+	account, err := fromBech32(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountHex := hex.EncodeToString(account)
+	pub := secp256k1.GenPrivKeySecp256k1([]byte("launch")).PubKey().(secp256k1.PubKeySecp256k1)
+	zero := strings.Repeat("00", sdk.AddrLen)
+
+	cases := []struct {
+		in   string
+		form string
+		want sdk.AccAddress // nil when the address must be rejected
+	}{
+		{testAddress, AddressBech32, account},
+		{accountHex, AddressHex, account},
+		{strings.ToUpper(accountHex), AddressHex, account},
+		{"0x" + accountHex, AddressEth, nil},
+		{"0X" + accountHex, AddressEth, nil},
+		{hex.EncodeToString(pub[:]), AddressPubKey, sdk.AccAddress(pub.Address())},
+		{"0x" + zero, AddressEth, nil},
+		{zero, AddressHex, nil},
+		{"0x" + accountHex[2:], AddressEth, nil},
+		{"0x" + accountHex + "00", AddressEth, nil},
+		{"0x" + strings.Repeat("zz", sdk.AddrLen), AddressEth, nil},
+		{"04" + hex.EncodeToString(pub[1:]), AddressPubKey, nil},
+		{"02" + strings.Repeat("ff", 32), AddressPubKey, nil},
+		{"cosmos1m3gmu4zlnv2hmqfu2jwr97r2653w9yshxkhfea", AddressBech32, nil},
+		{testAddress[:len(testAddress)-1] + "q", AddressBech32, nil},
+		{"", AddressBech32, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, form, err := ParseAddress(tc.in)
+			if form != tc.form {
+				t.Errorf("expected form %s, got %s", tc.form, form)
+			}
+			if (tc.want != nil) != (err == nil) {
+				t.Fatalf("expected %v, got err %v", tc.want, err)
+			}
+			if err == nil && !bytes.Equal(got, tc.want) {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestWriteConversionAudit(t *testing.T) {
+	account, err := fromBech32(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocs := []Allocation{
+		{Address: account, Source: "a.json"},
+		{Address: account, Source: "b.csv", Line: 2, Original: hex.EncodeToString(account), AddressForm: AddressHex},
+	}
+	if n := Conversions(allocs); n != 1 {
+		t.Errorf("expected 1 conversion, got %d", n)
+	}
+	var buf bytes.Buffer
+	if err := WriteConversionAudit(&buf, allocs); err != nil {
+		t.Fatal(err)
+	}
+	want := "source,form,original,address\nb.csv:2,hex," + hex.EncodeToString(account) + "," + testAddress + "\n"
+	if buf.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, buf.String())
+	}
+
+	// without conversions the audit still replaces an earlier one
+	buf.Reset()
+	if err := WriteConversionAudit(&buf, allocs[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if want := "source,form,original,address\n"; buf.String() != want {
+		t.Errorf("expected only the header, got\n%s", buf.String())
+	}
+}
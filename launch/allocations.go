@@ -0,0 +1,301 @@
+package launch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Allocation is a single address -> amount entry of an allocation file
+type Allocation struct {
+	Address sdk.AccAddress
+	Denom   string // okb, unless it comes from a tokens file
+	Amount  sdk.Dec
+	Source  string // file the entry was read from
+	Line    int    // row of a csv file, 0 otherwise
+
+	Category string // optional, from a csv file
+	Label    string
+
+	// the address as given when it wasn't bech32, see ParseAddress
+	Original    string
+	AddressForm string
+
+	// optional vesting schedule of the Vesting part of the amount
+	Lock    *Lock
+	Vesting sdk.Dec
+}
+
+// Position is the file, and the line of a csv row, the allocation comes from
+func (alloc Allocation) Position() string {
+	if alloc.Line > 0 {
+		return fmt.Sprintf("%s:%d", alloc.Source, alloc.Line)
+	}
+	return alloc.Source
+}
+
+// Account is an allocation with an optional lock, see ParseLock.
+// Without a vesting amount the whole amount is locked.
+type Account struct {
+	Address string      `json:"addr"`
+	Amount  json.Number `json:"amount"`
+	Lock    string      `json:"lock,omitempty"`
+	Vesting json.Number `json:"vesting,omitempty"`
+}
+
+// MultisigAccount is an allocation to a threshold multisig address,
+// optionally locked like an Account
+type MultisigAccount struct {
+	Address   string      `json:"addr"`
+	Threshold int         `json:"threshold"`
+	Pubs      []string    `json:"pubs"`
+	Amount    json.Number `json:"amount"`
+	Lock      string      `json:"lock,omitempty"`
+	Vesting   json.Number `json:"vesting,omitempty"`
+}
+
+// ReadListAllocations loads a flattened list of (addr, amt) pairs.
+// Duplicates within the file are reported as ErrDuplicateAddress.
+func ReadListAllocations(file string) ([]Allocation, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+
+	var l []interface{}
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	if err := dec.Decode(&l); err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+	if err := checkEOF(dec); err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+
+	// list should be pairs of addr, amt
+	if len(l)%2 != 0 {
+		return nil, ErrOddList{file, len(l)}
+	}
+
+	var allocs []Allocation
+	for i := 0; i < len(l); i += 2 {
+		addr, ok := l[i].(string)
+		if !ok {
+			return nil, ErrBadEntry{file, l[i], fmt.Sprintf("element %d should be an address", i)}
+		}
+		amt, ok := l[i+1].(json.Number)
+		if !ok {
+			return nil, ErrBadEntry{file, l[i+1], fmt.Sprintf("element %d should be the amount of %s", i+1, addr)}
+		}
+		alloc, err := newAllocation(file, addr, amt)
+		if err != nil {
+			return nil, err
+		}
+		allocs = append(allocs, alloc)
+	}
+	return allocs, checkDuplicates(allocs)
+}
+
+// ReadObjectAllocations loads a JSON object of addr->amt.
+// The object is decoded token by token so duplicate keys are not lost.
+func ReadObjectAllocations(file string) ([]Allocation, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, ErrBadFile{file, errors.New("expected a JSON object of addr->amt")}
+	}
+
+	var allocs []Allocation
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, ErrBadFile{file, err}
+		}
+		addr := tok.(string)
+		var amt json.Number
+		if err := dec.Decode(&amt); err != nil {
+			return nil, ErrBadEntry{file, addr, fmt.Sprintf("amount is not a number: %v", err)}
+		}
+		alloc, err := newAllocation(file, addr, amt)
+		if err != nil {
+			return nil, err
+		}
+		allocs = append(allocs, alloc)
+	}
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('}') {
+		return nil, ErrBadFile{file, errors.New("truncated JSON object of addr->amt")}
+	}
+	if err := checkEOF(dec); err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+	return allocs, checkDuplicates(allocs)
+}
+
+// checkEOF makes sure nothing follows the decoded value
+func checkEOF(dec *json.Decoder) error {
+	if dec.More() {
+		return errors.New("unexpected data after the top-level value")
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("unexpected data after the top-level value")
+	}
+	return nil
+}
+
+// ReadAccountAllocations loads a JSON list of accounts
+func ReadAccountAllocations(file string) ([]Allocation, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+
+	var accs []Account
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&accs); err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+	if err := checkEOF(dec); err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+
+	var allocs []Allocation
+	for _, acc := range accs {
+		alloc, err := newAllocation(file, acc.Address, acc.Amount)
+		if err != nil {
+			return nil, err
+		}
+		if err := alloc.setLock(acc.Lock, acc.Vesting); err != nil {
+			return nil, err
+		}
+		allocs = append(allocs, alloc)
+	}
+	return allocs, checkDuplicates(allocs)
+}
+
+// ReadMultisigAllocations loads a JSON list of multisig accounts and checks
+// every address against its threshold pubkey
+func ReadMultisigAllocations(file string) ([]Allocation, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+
+	var accs []MultisigAccount
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&accs); err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+	if err := checkEOF(dec); err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+
+	var allocs []Allocation
+	for _, acc := range accs {
+		alloc, err := newAllocation(file, acc.Address, acc.Amount)
+		if err != nil {
+			return nil, err
+		}
+		switch err := CheckMultisigAddress(acc.Threshold, acc.Pubs, acc.Address).(type) {
+		case nil:
+		case ErrMultisigAddress:
+			err.File = file
+			return nil, err
+		case ErrBadBech32:
+			err.File = file
+			return nil, err
+		default:
+			return nil, ErrBadEntry{file, acc.Address, err.Error()}
+		}
+		if err := alloc.setLock(acc.Lock, acc.Vesting); err != nil {
+			return nil, err
+		}
+		allocs = append(allocs, alloc)
+	}
+	return allocs, checkDuplicates(allocs)
+}
+
+func newAllocation(file, addr string, num json.Number) (Allocation, error) {
+	accAddr, form, err := ParseAddress(addr)
+	if err != nil && form == AddressBech32 {
+		return Allocation{}, ErrBadBech32{file, addr, err}
+	}
+	if err != nil {
+		return Allocation{}, ErrBadAddress{file, addr, form, err}
+	}
+	amt, err := ParseAmount(num, denomPrecision)
+	if err == errTooPrecise {
+		return Allocation{}, ErrPrecision{file, addr, num.String(), denomPrecision}
+	}
+	if err != nil || !amt.IsPositive() {
+		return Allocation{}, ErrBadAmount{file, addr, num.String()}
+	}
+	alloc := Allocation{Address: accAddr, Denom: okbDenomination, Amount: amt, Source: file}
+	if form != AddressBech32 {
+		alloc.Original, alloc.AddressForm = addr, form
+	}
+	return alloc, nil
+}
+
+// parse the lock schedule and the vesting amount of an allocation
+func (alloc *Allocation) setLock(lock string, vesting json.Number) error {
+	addr := alloc.Address.String()
+	if lock == "" {
+		if vesting != "" {
+			return ErrVesting{alloc.Source, addr, "vesting amount without a lock"}
+		}
+		return nil
+	}
+
+	l, err := ParseLock(lock)
+	if err != nil {
+		return ErrVesting{alloc.Source, addr, err.Error()}
+	}
+	alloc.Lock = l
+	alloc.Vesting = alloc.Amount
+	if vesting != "" {
+		alloc.Vesting, err = ParseAmount(vesting, denomPrecision)
+		if err != nil {
+			return ErrVesting{alloc.Source, addr, fmt.Sprintf("bad vesting amount %s", vesting)}
+		}
+	}
+	return nil
+}
+
+func checkDuplicates(allocs []Allocation) error {
+	seen := make(map[string]struct{}, len(allocs))
+	for _, alloc := range allocs {
+		addr := alloc.Address.String()
+		if _, ok := seen[addr]; ok {
+			return ErrDuplicateAddress{alloc.Source, addr, alloc.Source}
+		}
+		seen[addr] = struct{}{}
+	}
+	return nil
+}
+
+func fromBech32(address string) (sdk.AccAddress, error) {
+	bz, err := sdk.GetFromBech32(address, sdk.Bech32PrefixAccAddr)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) != sdk.AddrLen {
+		return nil, errors.New("incorrect address length")
+	}
+	return sdk.AccAddress(bz), nil
+}
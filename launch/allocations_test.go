@@ -0,0 +1,77 @@
+package launch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testAddress = "okchain102nlxa3n006qrdcwzj4ugwu9pds66jx9mc6j58"
+
+// writeTestFile writes content to name in a fresh temp dir
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "launch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestReadObjectAllocations(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		count   int
+		ok      bool
+	}{
+		{"valid", `{"` + testAddress + `": 10}`, 1, true},
+		{"empty", `{}`, 0, true},
+		{"trailing space", `{"` + testAddress + `": 10}` + "\n\n", 1, true},
+		{"truncated", `{"` + testAddress + `": 10`, 0, false},
+		{"trailing junk", `{"` + testAddress + `": 10} junk`, 0, false},
+		{"second object", `{"` + testAddress + `": 10}{}`, 0, false},
+		{"not an object", `["` + testAddress + `", 10]`, 0, false},
+		{"duplicate", `{"` + testAddress + `": 10, "` + testAddress + `": 5}`, 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			allocs, err := ReadObjectAllocations(writeTestFile(t, "object.json", tc.content))
+			if tc.ok != (err == nil) {
+				t.Fatalf("ok=%v, got err %v", tc.ok, err)
+			}
+			if tc.ok && len(allocs) != tc.count {
+				t.Errorf("expected %d allocations, got %d", tc.count, len(allocs))
+			}
+		})
+	}
+}
+
+func TestReadListAllocations(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		ok      bool
+	}{
+		{"valid", `["` + testAddress + `", 10]`, true},
+		{"truncated", `["` + testAddress + `", 10`, false},
+		{"trailing junk", `["` + testAddress + `", 10] junk`, false},
+		{"second list", `["` + testAddress + `", 10][]`, false},
+		{"odd", `["` + testAddress + `"]`, false},
+		{"amount first", `[10, "` + testAddress + `"]`, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadListAllocations(writeTestFile(t, "list.json", tc.content))
+			if tc.ok != (err == nil) {
+				t.Fatalf("ok=%v, got err %v", tc.ok, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package launch
+
+import (
+	"encoding/json"
+	"math/big"
+	"regexp"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// every denom of the chain is a sdk.Dec, so its precision is the Dec one
+const denomPrecision = sdk.Precision
+
+// plain decimal: digits with an optional fraction, no sign, exponent or base prefix
+var reAmount = regexp.MustCompile(`^\d+(\.\d+)?$`)
+
+// ParseAmount converts a json number into an exact decimal amount.
+// Only plain decimals are accepted, so neither float rounding, signs,
+// exponents nor fractions can sneak in; anything finer than the precision is an error.
+func ParseAmount(num json.Number, precision int) (sdk.Dec, error) {
+	s := num.String()
+	if !reAmount.MatchString(s) {
+		return sdk.Dec{}, errNotANumber
+	}
+	whole, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	if len(frac) > precision {
+		return sdk.Dec{}, errTooPrecise
+	}
+	units, _ := new(big.Int).SetString(whole+frac+strings.Repeat("0", precision-len(frac)), 10)
+	return sdk.NewDecFromBigIntWithPrec(units, int64(precision)), nil
+}
+
+// ratToDec converts a rational, failing when it is finer than the precision
+func ratToDec(r *big.Rat, precision int) (sdk.Dec, error) {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(precision)), nil)
+	r = new(big.Rat).Mul(r, new(big.Rat).SetInt(scale))
+	if !r.IsInt() {
+		return sdk.Dec{}, errTooPrecise
+	}
+	return sdk.NewDecFromBigIntWithPrec(r.Num(), int64(precision)), nil
+}
+
+// SumAllocations returns the exact total of the allocations
+func SumAllocations(allocs []Allocation) sdk.Dec {
+	total := sdk.ZeroDec()
+	for _, alloc := range allocs {
+		total = total.Add(alloc.Amount)
+	}
+	return total
+}
+
+// convert an amount of a denom to coins
+func newCoins(denom string, amt sdk.Dec) sdk.DecCoins {
+	return sdk.DecCoins{sdk.NewDecCoinFromDec(denom, amt)}
+}
+
+// convert a coin counted in units to the decimal amount users deal with
+func toDecCoin(coin sdk.Coin) sdk.DecCoin {
+	return sdk.NewDecCoinFromDec(coin.Denom, sdk.NewDecFromBigIntWithPrec(coin.Amount.BigInt(), sdk.Precision))
+}
@@ -0,0 +1,56 @@
+package launch
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string // empty when the amount must be rejected
+		err  error
+	}{
+		{"0", "0.00000000", nil},
+		{"1", "1.00000000", nil},
+		{"1000000000", "1000000000.00000000", nil},
+		{"0.5", "0.50000000", nil},
+		{"007.25", "7.25000000", nil},
+		{"1.12345678", "1.12345678", nil},
+		{"1.123456789", "", errTooPrecise},
+		{"1e3", "", errNotANumber},
+		{"1E-2", "", errNotANumber},
+		{"0x10", "", errNotANumber},
+		{"0b11", "", errNotANumber},
+		{"1/4", "", errNotANumber},
+		{"1_000", "", errNotANumber},
+		{"-5", "", errNotANumber},
+		{"+5", "", errNotANumber},
+		{".5", "", errNotANumber},
+		{"5.", "", errNotANumber},
+		{"1.2.3", "", errNotANumber},
+		{" 1", "", errNotANumber},
+		{"", "", errNotANumber},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseAmount(json.Number(tc.in), denomPrecision)
+			if err != tc.err {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if err == nil && got.String() != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseAmountPrecision(t *testing.T) {
+	if _, err := ParseAmount("1.5", 0); err != errTooPrecise {
+		t.Errorf("expected %v at precision 0, got %v", errTooPrecise, err)
+	}
+	got, err := ParseAmount("12", 0)
+	if err != nil || got.String() != "12.00000000" {
+		t.Errorf("expected 12 at precision 0, got %v %v", got, err)
+	}
+}
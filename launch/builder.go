@@ -0,0 +1,162 @@
+package launch
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/token"
+	"github.com/tendermint/go-amino"
+)
+
+const okbDenomination = "okb"
+
+// Builder accumulates the allocations and gentxs of a launch
+// and composes the genesis doc out of them
+type Builder struct {
+	ChainID         string
+	GenesisTime     time.Time
+	PowerThresholds PowerThresholds
+	AccountOrder    []string  // see DefaultAccountOrder
+	Profiles        []Profile // overlaid on the template params, in order
+
+	cdc         *amino.Codec
+	captain     sdk.AccAddress
+	allocations []Allocation
+	sources     map[string]string // denom and address -> file it was allocated in
+	tokens      []token.Token     // from a tokens file, the template ones otherwise
+	pairs       []token.TokenPair
+	genTxs      []GenTx
+
+	genTxReports []GenTxReport
+	powerReport  *PowerReport
+
+	manifestSources []Source
+}
+
+// NewBuilder returns an empty builder for the given chain
+func NewBuilder(chainID string, genesisTime time.Time) *Builder {
+	// XXX: the app state is decoded using amino JSON (eg. ints are strings)
+	// doesn't seem like we need to register anything though
+	return &Builder{
+		ChainID:         chainID,
+		GenesisTime:     genesisTime,
+		PowerThresholds: DefaultPowerThresholds(),
+		AccountOrder:    DefaultAccountOrder(),
+		cdc:             amino.NewCodec(),
+		sources:         make(map[string]string),
+	}
+}
+
+// Codec returns the codec used to encode the app state
+func (b *Builder) Codec() *amino.Codec {
+	return b.cdc
+}
+
+// AddCaptain loads the captain list file, which must hold exactly one account.
+// The captain becomes the owner of the genesis token.
+func (b *Builder) AddCaptain(file string) error {
+	allocs, err := ReadListAllocations(file)
+	if err != nil {
+		return err
+	}
+	if len(allocs) != 1 {
+		return ErrCaptainAccount{file, len(allocs)}
+	}
+	if err := b.AddAllocations(allocs); err != nil {
+		return err
+	}
+	b.captain = allocs[0].Address
+	return nil
+}
+
+// AddListFile loads a flattened (addr, amt) list file
+func (b *Builder) AddListFile(file string) error {
+	allocs, err := ReadListAllocations(file)
+	if err != nil {
+		return err
+	}
+	return b.AddAllocations(allocs)
+}
+
+// AddObjectFile loads an addr->amt object file
+func (b *Builder) AddObjectFile(file string) error {
+	allocs, err := ReadObjectAllocations(file)
+	if err != nil {
+		return err
+	}
+	return b.AddAllocations(allocs)
+}
+
+// AddAccountsFile loads a list of accounts with optional lock schedules
+func (b *Builder) AddAccountsFile(file string) error {
+	allocs, err := ReadAccountAllocations(file)
+	if err != nil {
+		return err
+	}
+	return b.AddAllocations(allocs)
+}
+
+// AddMultisigFile loads a list of multisig accounts
+func (b *Builder) AddMultisigFile(file string) error {
+	allocs, err := ReadMultisigAllocations(file)
+	if err != nil {
+		return err
+	}
+	return b.AddAllocations(allocs)
+}
+
+// AddAllocations adds already loaded allocations.
+// An address can only be allocated once across all the inputs
+// and vesting schedules are checked against the genesis time.
+func (b *Builder) AddAllocations(allocs []Allocation) error {
+	for _, alloc := range allocs {
+		if err := alloc.checkVesting(b.GenesisTime); err != nil {
+			return err
+		}
+		addr := alloc.Address.String()
+		key := sourceKey(alloc.Denom, addr)
+		if prev, ok := b.sources[key]; ok {
+			return ErrDuplicateAddress{alloc.Position(), addr, prev}
+		}
+		b.sources[key] = alloc.Source
+		b.allocations = append(b.allocations, alloc)
+	}
+	return nil
+}
+
+// Allocations returns everything allocated so far, in insertion order
+func (b *Builder) Allocations() []Allocation {
+	return b.allocations
+}
+
+// an address holds one allocation per denom
+func sourceKey(denom, addr string) string {
+	return denom + "/" + addr
+}
+
+// GenesisAccounts composes the genesis accounts from the allocations,
+// one per address holding all its denoms, numbered in the account order
+func (b *Builder) GenesisAccounts() []app.GenesisAccount {
+	genesisAccounts := make([]app.GenesisAccount, 0, len(b.allocations))
+	index := make(map[string]int, len(b.allocations))
+	for _, alloc := range b.allocations {
+		addr := alloc.Address.String()
+		acc := alloc.genesisAccount(b.GenesisTime)
+		if i, ok := index[addr]; ok {
+			// only okb allocations can be locked, so there is one schedule at most
+			merged := &genesisAccounts[i]
+			merged.Coins = merged.Coins.Add(acc.Coins)
+			if !acc.OriginalVesting.IsZero() {
+				merged.OriginalVesting = acc.OriginalVesting
+				merged.StartTime, merged.EndTime = acc.StartTime, acc.EndTime
+			}
+			continue
+		}
+		index[addr] = len(genesisAccounts)
+		genesisAccounts = append(genesisAccounts, acc)
+	}
+
+	b.numberAccounts(genesisAccounts)
+	return genesisAccounts
+}
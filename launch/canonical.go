@@ -0,0 +1,60 @@
+package launch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+
+	"github.com/ok-chain/okchain/app"
+)
+
+// CanonicalJSON re-encodes a json document with sorted object keys, two
+// spaces indentation and a final newline. Numbers and strings are kept as
+// they are, so the document means the same to amino.
+func CanonicalJSON(bz []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	// encoding/json sorts the keys of maps
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GenesisHash returns the sha256 of the canonical form of a genesis file,
+// the same as the sha256 of the file written by WriteGenesisDoc
+func GenesisHash(genesisFile string) ([]byte, error) {
+	bz, err := ioutil.ReadFile(genesisFile)
+	if err != nil {
+		return nil, ErrBadFile{genesisFile, err}
+	}
+	canonical, err := CanonicalJSON(bz)
+	if err != nil {
+		return nil, ErrBadFile{genesisFile, err}
+	}
+	hash := sha256.Sum256(canonical)
+	return hash[:], nil
+}
+
+// sortGenesisState puts the lists of the app state in a canonical order,
+// so the genesis doesn't depend on how the inputs were read. The accounts
+// are already numbered in order by GenesisAccounts and the gentxs by AddGenTxs.
+func sortGenesisState(genesisState *app.GenesisState) {
+	for i := range genesisState.Accounts {
+		genesisState.Accounts[i].Coins = genesisState.Accounts[i].Coins.Sort()
+	}
+
+	tokens := genesisState.Token.Info
+	sort.SliceStable(tokens, func(i, j int) bool { return tokens[i].Symbol < tokens[j].Symbol })
+}
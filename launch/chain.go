@@ -0,0 +1,89 @@
+package launch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/ok-chain/okchain/x/backend"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// layout of the backend maintain.conf genesis_time, e.g. "2019-05-01 00:00:00"
+const maintainTimeLayout = "2006-01-02 15:04:05"
+
+// ErrGenesisTimePassed is returned when the genesis time is not in the future
+type ErrGenesisTimePassed struct {
+	GenesisTime time.Time
+	Now         time.Time
+}
+
+func (e ErrGenesisTimePassed) Error() string {
+	return fmt.Sprintf("genesis time %s is not after %s",
+		e.GenesisTime.UTC().Format(time.RFC3339), e.Now.UTC().Format(time.RFC3339))
+}
+
+// ErrMaintainConfMismatch is returned when the backend genesis time of a
+// maintain.conf is not the genesis time of the chain
+type ErrMaintainConfMismatch struct {
+	File        string
+	Got         string
+	GenesisTime time.Time
+}
+
+func (e ErrMaintainConfMismatch) Error() string {
+	return fmt.Sprintf("%s: genesis_time is %q, expected %q", e.File, e.Got,
+		e.GenesisTime.UTC().Format(maintainTimeLayout))
+}
+
+// CheckChainID checks the chain id is usable by tendermint
+func CheckChainID(chainID string) error {
+	if chainID == "" {
+		return errors.New("empty chain id")
+	}
+	if len(chainID) > tmtypes.MaxChainIDLen {
+		return fmt.Errorf("chain id %q is longer than %d characters", chainID, tmtypes.MaxChainIDLen)
+	}
+	return nil
+}
+
+// CheckGenesisTime checks the chain starts after now
+func CheckGenesisTime(genesisTime, now time.Time) error {
+	if !genesisTime.After(now) {
+		return ErrGenesisTimePassed{genesisTime, now}
+	}
+	return nil
+}
+
+// MaintainConf returns the default backend config with the genesis time of the chain
+func MaintainConf(genesisTime time.Time) *backend.MaintainConf {
+	conf := backend.GetDefaultMaintainConfig()
+	conf.GenesisTime = genesisTime.UTC().Format(maintainTimeLayout)
+	return conf
+}
+
+// WriteMaintainConf writes a maintain.conf the way okchaind does
+func WriteMaintainConf(conf *backend.MaintainConf, file string) error {
+	bz, err := json.MarshalIndent(conf, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, bz, 0644)
+}
+
+// CheckMaintainConf loads a maintain.conf the way okchaind does and checks
+// its genesis time is the genesis time of the chain
+func CheckMaintainConf(file string, genesisTime time.Time) error {
+	conf, err := backend.LoadMaintainConf(filepath.Dir(file), filepath.Base(file))
+	if err != nil {
+		return ErrBadFile{file, err}
+	}
+	got, err := time.Parse(maintainTimeLayout, conf.GenesisTime)
+	if err != nil || !got.Equal(genesisTime.UTC()) {
+		return ErrMaintainConfMismatch{file, conf.GenesisTime, genesisTime}
+	}
+	return nil
+}
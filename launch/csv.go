@@ -0,0 +1,135 @@
+package launch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ok-chain/okchain/x/token"
+)
+
+// columns of a csv allocation file, address and amount are required
+var csvColumns = []string{"address", "amount", "denom", "category", "label", "lock", "vesting"}
+
+// ReadCSVAllocations loads allocation rows from a csv file, or a tsv one
+// when its extension is .tsv. The first row names the columns: address and
+// amount, then optionally denom (okb by default), category, label and the
+// lock and vesting of an Account. Lines starting with # are comments.
+// Every allocation keeps its line so errors and reports point at the row,
+// which needs csv.Reader.FieldPos and so Go 1.17 or later.
+func ReadCSVAllocations(file string) ([]Allocation, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	if filepath.Ext(file) == ".tsv" {
+		r.Comma = '\t'
+	}
+	r.Comment = '#'
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, ErrBadFile{file, fmt.Errorf("expected a header row: %v", err)}
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !containsString(csvColumns, name) {
+			return nil, ErrBadFile{file, fmt.Errorf("unknown column %q, expected %s", name, strings.Join(csvColumns, ", "))}
+		}
+		if _, ok := columns[name]; ok {
+			return nil, ErrBadFile{file, fmt.Errorf("column %q given twice", name)}
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["address"]; !ok {
+		return nil, ErrBadFile{file, fmt.Errorf("no address column")}
+	}
+	if _, ok := columns["amount"]; !ok {
+		return nil, ErrBadFile{file, fmt.Errorf("no amount column")}
+	}
+
+	var allocs []Allocation
+	rows := make(map[string]string) // denom and address -> position of its row
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, ErrBadFile{file, err}
+		}
+		line, _ := r.FieldPos(0)
+		pos := fmt.Sprintf("%s:%d", file, line)
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		// ParseAmount is strict too, but the cell is checked here to name the column
+		for _, name := range []string{"amount", "vesting"} {
+			if value := field(name); value != "" && !reAmount.MatchString(value) {
+				return nil, ErrBadEntry{pos, value, fmt.Sprintf("%s must be a plain decimal like 1000 or 0.5", name)}
+			}
+		}
+		if field("amount") == "" {
+			return nil, ErrBadEntry{pos, field("address"), "missing amount"}
+		}
+
+		alloc, err := newAllocation(pos, field("address"), json.Number(field("amount")))
+		if err != nil {
+			return nil, err
+		}
+		if denom := field("denom"); denom != "" {
+			if !token.ValidCoinName(denom) {
+				return nil, ErrBadEntry{pos, denom, "bad denom"}
+			}
+			alloc.Denom = denom
+		}
+		if err := alloc.setLock(field("lock"), json.Number(field("vesting"))); err != nil {
+			return nil, err
+		}
+		if alloc.Lock != nil && alloc.Denom != okbDenomination {
+			return nil, ErrVesting{pos, alloc.Address.String(), "only okb can be locked"}
+		}
+
+		key := sourceKey(alloc.Denom, alloc.Address.String())
+		if prev, ok := rows[key]; ok {
+			return nil, ErrDuplicateAddress{pos, alloc.Address.String(), prev}
+		}
+		rows[key] = pos
+
+		alloc.Source, alloc.Line = file, line
+		alloc.Category, alloc.Label = field("category"), field("label")
+		allocs = append(allocs, alloc)
+	}
+	return allocs, nil
+}
+
+// AddCSVFile loads a csv or tsv allocation file
+func (b *Builder) AddCSVFile(file string) error {
+	allocs, err := ReadCSVAllocations(file)
+	if err != nil {
+		return err
+	}
+	return b.AddAllocations(allocs)
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package launch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadCSVAllocations(t *testing.T) {
+	cases := []struct {
+		name    string
+		file    string
+		content string
+		count   int
+		errLine string // file suffix and line of the expected error, empty when valid
+	}{
+		{"minimal", "a.csv", "address,amount\n" + testAddress + ",10\n", 1, ""},
+		{"decimal", "a.csv", "address,amount\n" + testAddress + ",0.12345678\n", 1, ""},
+		{"all columns", "a.csv", "Address, Amount, Denom, Category, Label, Lock, Vesting\n" +
+			testAddress + ",10,okb,team,alice,+1y,5\n", 1, ""},
+		{"tsv", "a.tsv", "address\tamount\n" + testAddress + "\t10\n", 1, ""},
+		{"comments", "a.csv", "# allocations\naddress,amount\n# none yet\n", 0, ""},
+		{"denoms", "a.csv", "address,amount,denom\n" + testAddress + ",10,okb\n" + testAddress + ",10,xyz\n", 2, ""},
+		{"hex", "a.csv", "amount,address\n10," + strings.Repeat("12", 20) + "\n", 1, ""},
+		{"0x address", "a.csv", "amount,address\n10,0x" + strings.Repeat("12", 20) + "\n", 0, "a.csv:2"},
+		{"hex amount", "a.csv", "address,amount\n" + testAddress + ",0x10\n", 0, "a.csv:2"},
+		{"binary amount", "a.csv", "address,amount\n" + testAddress + ",0b11\n", 0, "a.csv:2"},
+		{"fraction amount", "a.csv", "address,amount\n" + testAddress + ",1/4\n", 0, "a.csv:2"},
+		{"underscore amount", "a.csv", "address,amount\n" + testAddress + ",1_000\n", 0, "a.csv:2"},
+		{"negative amount", "a.csv", "address,amount\n" + testAddress + ",-5\n", 0, "a.csv:2"},
+		{"exponent amount", "a.csv", "address,amount\n" + testAddress + ",1e3\n", 0, "a.csv:2"},
+		{"missing amount", "a.csv", "address,amount\n" + testAddress + ",\n", 0, "a.csv:2"},
+		{"zero amount", "a.csv", "address,amount\n" + testAddress + ",0\n", 0, "a.csv:2"},
+		{"too precise", "a.csv", "address,amount\n" + testAddress + ",0.123456789\n", 0, "a.csv:2"},
+		{"bad vesting", "a.csv", "address,amount,lock,vesting\n" + testAddress + ",10,+1y,1e1\n", 0, "a.csv:2"},
+		{"line after comment", "a.csv", "address,amount\n# skipped\n\n" + testAddress + ",-1\n", 0, "a.csv:4"},
+		{"bad denom", "a.csv", "address,amount,denom\n" + testAddress + ",10,OKB!\n", 0, "a.csv:2"},
+		{"locked token", "a.csv", "address,amount,denom,lock,vesting\n" + testAddress + ",10,xyz,+1y,5\n", 0, "a.csv:2"},
+		{"duplicate", "a.csv", "address,amount\n" + testAddress + ",10\n" + testAddress + ",5\n", 0, "a.csv:3"},
+		{"unknown column", "a.csv", "address,amount,note\n", 0, "a.csv"},
+		{"no amount column", "a.csv", "address\n", 0, "a.csv"},
+		{"empty", "a.csv", "", 0, "a.csv"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			allocs, err := ReadCSVAllocations(writeTestFile(t, tc.file, tc.content))
+			if tc.errLine == "" {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				if len(allocs) != tc.count {
+					t.Errorf("expected %d allocations, got %d", tc.count, len(allocs))
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expected an error at %s", tc.errLine)
+			}
+			if !strings.Contains(err.Error(), tc.errLine) {
+				t.Errorf("expected an error at %s, got %v", tc.errLine, err)
+			}
+		})
+	}
+}
+
+func TestReadCSVAllocationsLines(t *testing.T) {
+	content := "address,amount,category,label\n# team\n" + testAddress + ",10,team,alice\n"
+	allocs, err := ReadCSVAllocations(writeTestFile(t, "a.csv", content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(allocs) != 1 {
+		t.Fatalf("expected 1 allocation, got %d", len(allocs))
+	}
+	alloc := allocs[0]
+	if alloc.Line != 3 || alloc.Category != "team" || alloc.Label != "alice" || alloc.Denom != okbDenomination {
+		t.Errorf("unexpected allocation %+v", alloc)
+	}
+}
@@ -0,0 +1,319 @@
+package launch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/token"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// ChangeKind tells whether a value was added, removed or changed
+type ChangeKind string
+
+// kinds of Change
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "changed"
+)
+
+// Change is a value that differs between two genesis files. Old is only set
+// when the value was removed or changed, New when it was added or changed.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	Path string     `json:"path"`
+	Old  string     `json:"old,omitempty"`
+	New  string     `json:"new,omitempty"`
+}
+
+func added(path, newValue string) Change {
+	return Change{Kind: ChangeAdded, Path: path, New: newValue}
+}
+
+func removed(path, oldValue string) Change {
+	return Change{Kind: ChangeRemoved, Path: path, Old: oldValue}
+}
+
+func changed(path, oldValue, newValue string) Change {
+	return Change{Kind: ChangeModified, Path: path, Old: oldValue, New: newValue}
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ %s %s", c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("- %s %s", c.Path, c.Old)
+	default:
+		return fmt.Sprintf("~ %s %s -> %s", c.Path, c.Old, c.New)
+	}
+}
+
+// GenesisDiff holds the semantic differences between two genesis files
+type GenesisDiff struct {
+	ChainID         *Change  `json:"chain_id,omitempty"`
+	GenesisTime     *Change  `json:"genesis_time,omitempty"`
+	ConsensusParams []Change `json:"consensus_params,omitempty"` // by param path
+	Accounts        []Change `json:"accounts,omitempty"`         // by address
+	Params          []Change `json:"params,omitempty"`           // by module.param path
+	GenTxs          []Change `json:"gentxs,omitempty"`           // by validator address
+}
+
+// Empty is true when both genesis files mean the same
+func (d GenesisDiff) Empty() bool {
+	return d.ChainID == nil && d.GenesisTime == nil && len(d.ConsensusParams) == 0 &&
+		len(d.Accounts) == 0 && len(d.Params) == 0 && len(d.GenTxs) == 0
+}
+
+// DiffGenesis compares two genesis files section by section
+func DiffGenesis(oldDoc, newDoc *tmtypes.GenesisDoc, oldState, newState app.GenesisState) (GenesisDiff, error) {
+	var d GenesisDiff
+	if oldDoc.ChainID != newDoc.ChainID {
+		c := changed("chain_id", oldDoc.ChainID, newDoc.ChainID)
+		d.ChainID = &c
+	}
+	if !oldDoc.GenesisTime.Equal(newDoc.GenesisTime) {
+		c := changed("genesis_time", oldDoc.GenesisTime.Format(time.RFC3339), newDoc.GenesisTime.Format(time.RFC3339))
+		d.GenesisTime = &c
+	}
+
+	var err error
+	d.ConsensusParams, err = diffValues("", oldDoc.ConsensusParams, newDoc.ConsensusParams)
+	if err != nil {
+		return d, err
+	}
+
+	d.Accounts = diffAccounts(oldState.Accounts, newState.Accounts)
+
+	oldSections, newSections := paramSections(oldState), paramSections(newState)
+	for i := range oldSections {
+		changes, err := diffValues(oldSections[i].module, oldSections[i].params, newSections[i].params)
+		if err != nil {
+			return d, err
+		}
+		d.Params = append(d.Params, changes...)
+	}
+	changes, err := diffValues("mint.minter", oldState.MintData.Minter, newState.MintData.Minter)
+	if err != nil {
+		return d, err
+	}
+	d.Params = append(d.Params, changes...)
+	changes, err = diffTokens(oldState.Token.Info, newState.Token.Info)
+	if err != nil {
+		return d, err
+	}
+	d.Params = append(d.Params, changes...)
+
+	d.GenTxs = diffGenTxs(oldState.GenTxs, newState.GenTxs)
+	return d, nil
+}
+
+// diffValues compares the amino json of two values leaf by leaf
+func diffValues(prefix string, oldValue, newValue interface{}) ([]Change, error) {
+	oldLeaves, err := jsonLeaves(prefix, oldValue)
+	if err != nil {
+		return nil, err
+	}
+	newLeaves, err := jsonLeaves(prefix, newValue)
+	if err != nil {
+		return nil, err
+	}
+	return diffMaps(oldLeaves, newLeaves), nil
+}
+
+// jsonLeaves flattens the amino json of a value into dotted paths
+func jsonLeaves(prefix string, value interface{}) (map[string]string, error) {
+	bz, err := appCdc.MarshalJSON(value)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(bz))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	leaves := make(map[string]string)
+	flattenJSON(prefix, doc, leaves)
+	return leaves, nil
+}
+
+func flattenJSON(path string, v interface{}, leaves map[string]string) {
+	join := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + "." + key
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			leaves[path] = "{}"
+		}
+		for key, value := range v {
+			flattenJSON(join(key), value, leaves)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			leaves[path] = "[]"
+		}
+		for i, value := range v {
+			flattenJSON(join(strconv.Itoa(i)), value, leaves)
+		}
+	case nil:
+		leaves[path] = "null"
+	default:
+		leaves[path] = fmt.Sprint(v)
+	}
+}
+
+// diffMaps returns the changes between two maps, sorted by key
+func diffMaps(oldMap, newMap map[string]string) []Change {
+	var changes []Change
+	for key, oldValue := range oldMap {
+		newValue, ok := newMap[key]
+		if !ok {
+			changes = append(changes, removed(key, oldValue))
+		} else if oldValue != newValue {
+			changes = append(changes, changed(key, oldValue, newValue))
+		}
+	}
+	for key, newValue := range newMap {
+		if _, ok := oldMap[key]; !ok {
+			changes = append(changes, added(key, newValue))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// diffTokens compares the genesis tokens by symbol
+func diffTokens(oldTokens, newTokens []token.Token) ([]Change, error) {
+	oldMap := make(map[string]token.Token, len(oldTokens))
+	for _, t := range oldTokens {
+		oldMap[t.Symbol] = t
+	}
+	newMap := make(map[string]token.Token, len(newTokens))
+	for _, t := range newTokens {
+		newMap[t.Symbol] = t
+	}
+
+	var changes []Change
+	for symbol, oldToken := range oldMap {
+		path := "token.info." + symbol
+		newToken, ok := newMap[symbol]
+		if !ok {
+			bz, err := appCdc.MarshalJSON(oldToken)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, removed(path, string(bz)))
+			continue
+		}
+		tokenChanges, err := diffValues(path, oldToken, newToken)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, tokenChanges...)
+	}
+	for symbol, newToken := range newMap {
+		if _, ok := oldMap[symbol]; !ok {
+			bz, err := appCdc.MarshalJSON(newToken)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, added("token.info."+symbol, string(bz)))
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// diffAccounts compares the account numbers, balances and vesting schedules by address
+func diffAccounts(oldAccs, newAccs []app.GenesisAccount) []Change {
+	oldMap := make(map[string]string, len(oldAccs))
+	for _, acc := range oldAccs {
+		oldMap[acc.Address.String()] = describeAccount(acc)
+	}
+	newMap := make(map[string]string, len(newAccs))
+	for _, acc := range newAccs {
+		newMap[acc.Address.String()] = describeAccount(acc)
+	}
+	return diffMaps(oldMap, newMap)
+}
+
+func describeAccount(acc app.GenesisAccount) string {
+	// DecCoins.IsEqual panics on differing denoms, compare the canonical strings instead
+	desc := fmt.Sprintf("#%d %s", acc.AccountNumber, acc.Coins.Sort())
+	if !acc.OriginalVesting.IsZero() {
+		var vesting sdk.DecCoins
+		for _, coin := range acc.OriginalVesting {
+			vesting = append(vesting, toDecCoin(coin))
+		}
+		desc += fmt.Sprintf(" (vesting %v", vesting)
+		if acc.StartTime != 0 {
+			desc += " from " + time.Unix(acc.StartTime, 0).UTC().Format(time.RFC3339)
+		}
+		desc += " until " + time.Unix(acc.EndTime, 0).UTC().Format(time.RFC3339) + ")"
+	}
+	return desc
+}
+
+// diffGenTxs compares the gentxs by validator address, monikers need not be unique
+func diffGenTxs(oldGenTxs, newGenTxs []json.RawMessage) []Change {
+	return diffMaps(describeGenTxs(oldGenTxs), describeGenTxs(newGenTxs))
+}
+
+func describeGenTxs(genTxs []json.RawMessage) map[string]string {
+	descs := make(map[string]string, len(genTxs))
+	for i, genTx := range genTxs {
+		tx, msg, err := DecodeGenTx(genTx)
+		if err != nil {
+			descs[fmt.Sprintf("gentx #%d", i)] = err.Error()
+			continue
+		}
+		descs[msg.ValidatorAddress.String()] = fmt.Sprintf("%s %v rate %v %s",
+			msg.Description.Moniker, toDecCoin(msg.Value), msg.Commission.Rate, tx.GetMemo())
+	}
+	return descs
+}
+
+// WriteGenesisDiff prints the differences section by section
+func WriteGenesisDiff(w io.Writer, d GenesisDiff) error {
+	if d.ChainID != nil {
+		fmt.Fprintf(w, "chain id: %s -> %s\n", d.ChainID.Old, d.ChainID.New)
+	}
+	if d.GenesisTime != nil {
+		fmt.Fprintf(w, "genesis time: %s -> %s\n", d.GenesisTime.Old, d.GenesisTime.New)
+	}
+	for _, section := range []struct {
+		title   string
+		changes []Change
+	}{
+		{"consensus params", d.ConsensusParams},
+		{"accounts", d.Accounts},
+		{"params", d.Params},
+		{"gentxs", d.GenTxs},
+	} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		lines := make([]string, 0, len(section.changes))
+		for _, c := range section.changes {
+			lines = append(lines, c.String())
+		}
+		if _, err := fmt.Fprintf(w, "%s:\n  %s\n", section.title, strings.Join(lines, "\n  ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
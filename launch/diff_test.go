@@ -0,0 +1,116 @@
+package launch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/staking"
+	"github.com/ok-chain/okchain/x/token"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// testGenTx encodes an unsigned gentx creating a validator for the operator
+func testGenTx(t *testing.T, operator, moniker string, amount int64) json.RawMessage {
+	t.Helper()
+	valAddr := sdk.ValAddress([]byte(operator))
+	msg := staking.NewMsgCreateValidator(valAddr, ed25519.GenPrivKeyFromSecret([]byte(operator)).PubKey(),
+		sdk.NewCoin("okb", sdk.NewInt(amount)), staking.NewDescription(moniker, "", "", ""),
+		staking.NewCommissionMsg(sdk.NewDecWithPrec(1, 1), sdk.NewDecWithPrec(5, 1), sdk.NewDecWithPrec(1, 2)), sdk.OneInt())
+	bz, err := appCdc.MarshalJSON(auth.NewStdTx([]sdk.Msg{msg}, auth.StdFee{}, nil, "node@127.0.0.1:26656"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bz
+}
+
+func TestDiffGenesis(t *testing.T) {
+	alice := sdk.AccAddress([]byte("alice_______________"))
+	bob := sdk.AccAddress([]byte("bob_________________"))
+	carol := sdk.AccAddress([]byte("carol_______________"))
+	okb := func(amt int64) sdk.DecCoins { return newCoins("okb", sdk.NewDec(amt)) }
+	genesisTime := time.Date(2019, 3, 13, 23, 0, 0, 0, time.UTC)
+
+	oldDoc := &tmtypes.GenesisDoc{ChainID: "okchain", GenesisTime: genesisTime, ConsensusParams: tmtypes.DefaultConsensusParams()}
+	oldState := app.NewDefaultGenesisState()
+	oldState.Accounts = []app.GenesisAccount{{Address: alice, Coins: okb(10)}, {Address: bob, Coins: okb(20)}}
+	oldState.Token.Info = []token.Token{{Symbol: "okb", TotalSupply: 30}}
+	oldState.GenTxs = []json.RawMessage{testGenTx(t, "operator1___________", "same", 1), testGenTx(t, "operator2___________", "same", 1)}
+
+	newParams := *tmtypes.DefaultConsensusParams()
+	newParams.Block.MaxGas = 1000
+	newDoc := &tmtypes.GenesisDoc{ChainID: "okchain-2", GenesisTime: genesisTime, ConsensusParams: &newParams}
+	newState := app.NewDefaultGenesisState()
+	newState.Accounts = []app.GenesisAccount{{Address: alice, Coins: okb(15)}, {Address: carol, Coins: okb(15)}}
+	newState.Token.Info = []token.Token{{Symbol: "okb", TotalSupply: 30}, {Symbol: "xyz", TotalSupply: 1}}
+	newState.GenTxs = []json.RawMessage{testGenTx(t, "operator1___________", "same", 1), testGenTx(t, "operator2___________", "same", 2)}
+
+	d, err := DiffGenesis(oldDoc, newDoc, oldState, newState)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if d.ChainID == nil || d.ChainID.Kind != ChangeModified || d.ChainID.Old != "okchain" || d.ChainID.New != "okchain-2" {
+		t.Errorf("unexpected chain id change %+v", d.ChainID)
+	}
+	if d.GenesisTime != nil {
+		t.Errorf("unexpected genesis time change %+v", d.GenesisTime)
+	}
+
+	cases := []struct {
+		section string
+		changes []Change
+		want    []ChangeKind // by sorted path
+		paths   []string
+	}{
+		{"consensus params", d.ConsensusParams, []ChangeKind{ChangeModified}, []string{"block.max_gas"}},
+		{"accounts", d.Accounts, []ChangeKind{ChangeModified, ChangeAdded, ChangeRemoved},
+			[]string{alice.String(), carol.String(), bob.String()}},
+		{"params", d.Params, []ChangeKind{ChangeAdded}, []string{"token.info.xyz"}},
+		{"gentxs", d.GenTxs, []ChangeKind{ChangeModified},
+			[]string{sdk.ValAddress([]byte("operator2___________")).String()}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.section, func(t *testing.T) {
+			if len(tc.changes) != len(tc.want) {
+				t.Fatalf("expected %d changes, got %v", len(tc.want), tc.changes)
+			}
+			for i, c := range tc.changes {
+				if c.Kind != tc.want[i] || c.Path != tc.paths[i] {
+					t.Errorf("change %d: expected %s %s, got %s %s", i, tc.want[i], tc.paths[i], c.Kind, c.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffMapsEmptyValues(t *testing.T) {
+	// an empty value must not be mistaken for a missing one
+	changes := diffMaps(map[string]string{"a": "", "b": "x"}, map[string]string{"a": "y", "b": "", "c": ""})
+	want := []Change{changed("a", "", "y"), changed("b", "x", ""), added("c", "")}
+	if len(changes) != len(want) {
+		t.Fatalf("expected %v, got %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("change %d: expected %+v, got %+v", i, want[i], changes[i])
+		}
+	}
+}
+
+func TestDiffGenesisIdentical(t *testing.T) {
+	doc := &tmtypes.GenesisDoc{ChainID: "okchain", ConsensusParams: tmtypes.DefaultConsensusParams()}
+	state := app.NewDefaultGenesisState()
+	state.GenTxs = []json.RawMessage{testGenTx(t, "operator1___________", "star", 1)}
+	d, err := DiffGenesis(doc, doc, state, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Empty() {
+		t.Errorf("expected no differences, got %+v", d)
+	}
+}
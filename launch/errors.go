@@ -0,0 +1,131 @@
+package launch
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errNotANumber = errors.New("not a number")
+	errTooPrecise = errors.New("too much precision")
+)
+
+// ErrBadFile is returned when an input file can't be read or decoded
+type ErrBadFile struct {
+	File string
+	Err  error
+}
+
+func (e ErrBadFile) Error() string {
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+// ErrOddList is returned when a flattened (addr, amt) list has an odd length
+type ErrOddList struct {
+	File   string
+	Length int
+}
+
+func (e ErrOddList) Error() string {
+	return fmt.Sprintf("%s: list length %d is odd, expected (addr, amt) pairs", e.File, e.Length)
+}
+
+// ErrBadEntry is returned when an entry of an allocation file has the wrong shape
+type ErrBadEntry struct {
+	File   string
+	Entry  interface{}
+	Reason string
+}
+
+func (e ErrBadEntry) Error() string {
+	return fmt.Sprintf("%s: bad entry %v: %s", e.File, e.Entry, e.Reason)
+}
+
+// ErrBadBech32 is returned when an address or a pubkey isn't valid bech32
+type ErrBadBech32 struct {
+	File    string
+	Address string
+	Err     error
+}
+
+func (e ErrBadBech32) Error() string {
+	return fmt.Sprintf("%s: bad bech32 %q: %v", e.File, e.Address, e.Err)
+}
+
+// ErrBadAddress is returned when a hex address or pubkey can't be converted to bech32
+type ErrBadAddress struct {
+	File    string
+	Address string
+	Form    string
+	Err     error
+}
+
+func (e ErrBadAddress) Error() string {
+	return fmt.Sprintf("%s: bad %s address %q: %v", e.File, e.Form, e.Address, e.Err)
+}
+
+// ErrBadAmount is returned when an allocated amount is not a positive number
+type ErrBadAmount struct {
+	File    string
+	Address string
+	Amount  string
+}
+
+func (e ErrBadAmount) Error() string {
+	return fmt.Sprintf("%s: amount for addr (%s) must be a positive number: %s", e.File, e.Address, e.Amount)
+}
+
+// ErrPrecision is returned when an amount has more decimals than its denom supports
+type ErrPrecision struct {
+	File      string
+	Address   string
+	Amount    string
+	Precision int
+}
+
+func (e ErrPrecision) Error() string {
+	return fmt.Sprintf("%s: amount %s for addr (%s) exceeds the %d decimals precision", e.File, e.Amount, e.Address, e.Precision)
+}
+
+// ErrVesting is returned when a vesting schedule is inconsistent
+type ErrVesting struct {
+	File    string
+	Address string
+	Reason  string
+}
+
+func (e ErrVesting) Error() string {
+	return fmt.Sprintf("%s: bad vesting for addr (%s): %s", e.File, e.Address, e.Reason)
+}
+
+// ErrDuplicateAddress is returned when an address is allocated more than once
+type ErrDuplicateAddress struct {
+	File     string
+	Address  string
+	PrevFile string
+}
+
+func (e ErrDuplicateAddress) Error() string {
+	return fmt.Sprintf("%s: duplicate addr %s, already allocated in %s", e.File, e.Address, e.PrevFile)
+}
+
+// ErrCaptainAccount is returned when the captain file doesn't hold exactly one account
+type ErrCaptainAccount struct {
+	File  string
+	Count int
+}
+
+func (e ErrCaptainAccount) Error() string {
+	return fmt.Sprintf("%s: expected exactly one captain account, got %d", e.File, e.Count)
+}
+
+// ErrMultisigAddress is returned when a multisig address doesn't match its threshold pubkey
+type ErrMultisigAddress struct {
+	File     string
+	Address  string
+	Computed string
+}
+
+func (e ErrMultisigAddress) Error() string {
+	return fmt.Sprintf("%s: computed addr (%s) does not match given addr (%s)", e.File, e.Computed, e.Address)
+}
@@ -0,0 +1,115 @@
+package launch
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/token"
+	"github.com/tendermint/go-amino"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// GenesisDoc json marshals the initial app state (accounts and gentx)
+// and adds them to the template
+func (b *Builder) GenesisDoc(genesisTemplate string) (*tmtypes.GenesisDoc, error) {
+	if b.captain == nil {
+		return nil, errors.New("no captain account")
+	}
+
+	// read the template with the params
+	genesisDoc, err := tmtypes.GenesisDocFromFile(genesisTemplate)
+	if err != nil {
+		return nil, ErrBadFile{genesisTemplate, err}
+	}
+	// set genesis time and chain id
+	genesisDoc.GenesisTime = b.GenesisTime
+	genesisDoc.ChainID = b.ChainID
+
+	// read the app state from the generic tendermint app state bytes
+	// and populate with the accounts and gentxs
+	var genesisState app.GenesisState
+	err = b.cdc.UnmarshalJSON(genesisDoc.AppState, &genesisState)
+	if err != nil {
+		return nil, ErrBadFile{genesisTemplate, err}
+	}
+	for _, profile := range b.Profiles {
+		if err := profile.Apply(&genesisState); err != nil {
+			return nil, err
+		}
+	}
+
+	genesisState.Accounts = b.GenesisAccounts()
+	genesisState.GenTxs = nil
+	for _, genTx := range b.genTxs {
+		genesisState.GenTxs = append(genesisState.GenTxs, genTx.JSON)
+	}
+
+	// the tokens file replaces the template tokens, the first account owns those without an owner
+	if b.tokens != nil {
+		genesisState.Token.Info = append([]token.Token(nil), b.tokens...)
+	}
+	if err := b.setImplicitOwners(&genesisState); err != nil {
+		return nil, err
+	}
+
+	// fix staking data, the whole bond denom supply starts in the not bonded pool
+	genesisState.StakingData.Params.BondDenom = okbDenomination
+	notBonded := sdk.ZeroInt()
+	for _, acc := range genesisState.Accounts {
+		notBonded = notBonded.Add(sdk.NewIntFromBigInt(acc.Coins.AmountOf(okbDenomination).Int))
+	}
+	genesisState.StakingData.Pool.NotBondedTokens = notBonded
+
+	sortGenesisState(&genesisState)
+
+	if err := b.CheckSupply(genesisState); err != nil {
+		return nil, err
+	}
+	if err := b.CheckGenTxs(genesisState.Accounts, okbDenomination); err != nil {
+		return nil, err
+	}
+	if err := b.CheckPower(genesisState.StakingData.Params.MaxValidators); err != nil {
+		return nil, err
+	}
+	if err := ValidateGenesisState(genesisState); err != nil {
+		return nil, err
+	}
+
+	// marshal the app state back to json and update the genesisDoc
+	genesisStateJSON, err := b.cdc.MarshalJSON(genesisState)
+	if err != nil {
+		return nil, err
+	}
+	genesisDoc.AppState = genesisStateJSON
+
+	return genesisDoc, nil
+}
+
+// WriteGenesisDoc writes the genesis file as canonical json
+func WriteGenesisDoc(cdc *amino.Codec, genesisDoc *tmtypes.GenesisDoc, genesisFile string) error {
+	bz, err := cdc.MarshalJSON(genesisDoc)
+	if err != nil {
+		return err
+	}
+	bz, err = CanonicalJSON(bz)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(genesisFile, bz, 0600)
+}
+
+// LoadGenesis reads a genesis file and decodes its app state
+func LoadGenesis(cdc *amino.Codec, genesisFile string) (*tmtypes.GenesisDoc, app.GenesisState, error) {
+	var genesisState app.GenesisState
+	genesisDoc, err := tmtypes.GenesisDocFromFile(genesisFile)
+	if err != nil {
+		return nil, genesisState, ErrBadFile{genesisFile, err}
+	}
+	if err := cdc.UnmarshalJSON(genesisDoc.AppState, &genesisState); err != nil {
+		return nil, genesisState, ErrBadFile{genesisFile, fmt.Errorf("decoding app state: %v", err)}
+	}
+	return genesisDoc, genesisState, nil
+}
@@ -0,0 +1,265 @@
+package launch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/common"
+	"github.com/ok-chain/okchain/x/staking"
+)
+
+// the full app codec, gentxs hold interfaces (msgs, pubkeys)
+var appCdc = app.MakeCodec()
+
+// GenTx is a gentx file as loaded from the gentx directory
+type GenTx struct {
+	File string
+	JSON json.RawMessage
+}
+
+// GenTxReport is the outcome of the checks of one gentx file
+type GenTxReport struct {
+	File      string
+	Moniker   string
+	Validator string
+	ConsPub   string // bech32 consensus pubkey
+	Value     sdk.Coin
+	Memo      string
+	Peer      Peer
+	Err       error
+}
+
+// ErrGenTxs is returned when at least one gentx fails its checks
+type ErrGenTxs struct {
+	Reports []GenTxReport
+}
+
+func (e ErrGenTxs) Error() string {
+	var failed []string
+	for _, r := range e.Reports {
+		if r.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", r.File, r.Err))
+		}
+	}
+	return fmt.Sprintf("%d invalid gentx(s):\n  %s", len(failed), strings.Join(failed, "\n  "))
+}
+
+// AddGenTxs loads every json file of the gentx directory
+func (b *Builder) AddGenTxs(genTxPath string) error {
+	fs, err := ioutil.ReadDir(genTxPath)
+	if err != nil {
+		return ErrBadFile{genTxPath, err}
+	}
+
+	for _, f := range fs {
+		name := filepath.Join(genTxPath, f.Name())
+		if f.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+		bz, err := ioutil.ReadFile(name)
+		if err != nil {
+			return ErrBadFile{name, err}
+		}
+		b.genTxs = append(b.genTxs, GenTx{name, json.RawMessage(bz)})
+	}
+
+	// gentx files are named after their node id, which makes their order canonical
+	sort.SliceStable(b.genTxs, func(i, j int) bool {
+		return filepath.Base(b.genTxs[i].File) < filepath.Base(b.genTxs[j].File)
+	})
+	return nil
+}
+
+// GenTxs returns the loaded gentxs
+func (b *Builder) GenTxs() []GenTx {
+	return b.genTxs
+}
+
+// GenTxReports returns the reports of the last CheckGenTxs
+func (b *Builder) GenTxReports() []GenTxReport {
+	return b.genTxReports
+}
+
+// CheckGenTxs decodes and checks every gentx against the genesis accounts,
+// the way app.CollectStdTxs does but reporting on every file: exactly one
+// MsgCreateValidator with sane commission rates, signed for the chain id
+// with account number and sequence 0, by a delegator holding the bond.
+func (b *Builder) CheckGenTxs(accounts []app.GenesisAccount, bondDenom string) error {
+	addrMap := make(map[string]app.GenesisAccount, len(accounts))
+	for _, acc := range accounts {
+		addrMap[acc.Address.String()] = acc
+	}
+
+	b.genTxReports = nil
+	seen := make(map[string]string)     // validator -> file
+	consPubs := make(map[string]string) // consensus pubkey -> file
+	failed := false
+	for _, genTx := range b.genTxs {
+		report := checkGenTx(b.ChainID, genTx, addrMap, bondDenom)
+		if report.Err == nil {
+			if prev, ok := seen[report.Validator]; ok {
+				report.Err = fmt.Errorf("validator %s already created by %s", report.Validator, prev)
+			} else if prev, ok := consPubs[report.ConsPub]; ok {
+				report.Err = fmt.Errorf("consensus pubkey %s already used by %s", report.ConsPub, prev)
+			} else {
+				// the first file keeps the validator and the pubkey
+				seen[report.Validator] = genTx.File
+				consPubs[report.ConsPub] = genTx.File
+			}
+		}
+		if report.Err != nil {
+			failed = true
+		}
+		b.genTxReports = append(b.genTxReports, report)
+	}
+	if failed {
+		return ErrGenTxs{b.genTxReports}
+	}
+	return nil
+}
+
+// DecodeGenTx decodes a gentx and returns its only MsgCreateValidator
+func DecodeGenTx(bz []byte) (auth.StdTx, staking.MsgCreateValidator, error) {
+	var tx auth.StdTx
+	if err := appCdc.UnmarshalJSON(bz, &tx); err != nil {
+		return tx, staking.MsgCreateValidator{}, fmt.Errorf("decoding StdTx: %s", oneLine(err))
+	}
+
+	// genesis transactions must be single-message
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		return tx, staking.MsgCreateValidator{}, fmt.Errorf("expected a single message, got %d", len(msgs))
+	}
+	msg, ok := msgs[0].(staking.MsgCreateValidator)
+	if !ok {
+		return tx, staking.MsgCreateValidator{}, fmt.Errorf("expected a MsgCreateValidator, got %s", msgs[0].Type())
+	}
+	return tx, msg, nil
+}
+
+func checkGenTx(chainID string, genTx GenTx, addrMap map[string]app.GenesisAccount, bondDenom string) (report GenTxReport) {
+	report.File = genTx.File
+	tx, msg, err := DecodeGenTx(genTx.JSON)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	report.Moniker = msg.Description.Moniker
+	report.Validator = msg.ValidatorAddress.String()
+	report.Value = msg.Value
+	report.Memo = tx.GetMemo()
+
+	// the memo flag is used to store the ip and node-id
+	if len(report.Memo) == 0 {
+		report.Err = errors.New("couldn't find node's address and IP in the memo")
+		return report
+	}
+	if report.Peer, err = checkGenTxPeer(genTx.File, report.Memo); err != nil {
+		report.Err = err
+		return report
+	}
+	if err := tx.ValidateBasic(); err != nil {
+		report.Err = fmt.Errorf("invalid tx: %s", oneLine(err))
+		return report
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		report.Err = fmt.Errorf("invalid MsgCreateValidator: %s", oneLine(err))
+		return report
+	}
+	if msg.PubKey == nil {
+		report.Err = errors.New("invalid MsgCreateValidator: missing consensus pubkey")
+		return report
+	}
+	if report.ConsPub, err = sdk.Bech32ifyConsPub(msg.PubKey); err != nil {
+		report.Err = fmt.Errorf("invalid consensus pubkey: %v", err)
+		return report
+	}
+	commission := staking.NewCommission(msg.Commission.Rate, msg.Commission.MaxRate, msg.Commission.MaxChangeRate)
+	if err := commission.Validate(); err != nil {
+		report.Err = fmt.Errorf("invalid commission %v: %s", msg.Commission, oneLine(err))
+		return report
+	}
+
+	if err := verifyGenTxSignatures(chainID, tx); err != nil {
+		report.Err = err
+		return report
+	}
+
+	// validate the delegator and its funds against the accounts in the state
+	if msg.Value.Denom != bondDenom {
+		report.Err = fmt.Errorf("delegation in %s, expected the bond denom %s", msg.Value.Denom, bondDenom)
+		return report
+	}
+	delAcc, ok := addrMap[msg.DelegatorAddress.String()]
+	if !ok {
+		report.Err = fmt.Errorf("delegator %s is not a genesis account", msg.DelegatorAddress)
+		return report
+	}
+	if common.ConvertDecCoinsToCoins(delAcc.Coins).AmountOf(bondDenom).LT(msg.Value.Amount) {
+		report.Err = fmt.Errorf("insufficient fund for delegation %v: %v < %v",
+			delAcc.Address, delAcc.Coins.AmountOf(bondDenom), toDecCoin(msg.Value).Amount)
+		return report
+	}
+	return report
+}
+
+// verifyGenTxSignatures checks there is one signature per signer, made for
+// the chain id with account number and sequence 0
+func verifyGenTxSignatures(chainID string, tx auth.StdTx) error {
+	signers := tx.GetSigners()
+	sigs := tx.GetSignatures()
+	if len(sigs) != len(signers) {
+		return fmt.Errorf("expected %d signature(s), got %d", len(signers), len(sigs))
+	}
+
+	signBytes := auth.StdSignBytes(chainID, 0, 0, tx.Fee, tx.GetMsgs(), tx.GetMemo())
+	for i, sig := range sigs {
+		if sig.PubKey == nil {
+			return fmt.Errorf("signature %d has no pubkey", i)
+		}
+		if !bytes.Equal(sig.PubKey.Address(), signers[i]) {
+			return fmt.Errorf("signature %d pubkey doesn't belong to signer %s", i, signers[i])
+		}
+		if !sig.PubKey.VerifyBytes(signBytes, sig.Signature) {
+			return fmt.Errorf("signature %d of %s doesn't verify for chain id %q", i, signers[i], chainID)
+		}
+	}
+	return nil
+}
+
+// WriteGenTxReports prints the gentx reports as a table
+func WriteGenTxReports(w io.Writer, reports []GenTxReport) error {
+	reports = append([]GenTxReport(nil), reports...)
+	sort.Slice(reports, func(i, j int) bool { return reports[i].File < reports[j].File })
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "GENTX\tMONIKER\tVALIDATOR\tSELF DELEGATION\tSTATUS")
+	for _, r := range reports {
+		status := "ok"
+		if r.Err != nil {
+			status = r.Err.Error()
+		}
+		value := ""
+		if r.Value.Denom != "" {
+			value = toDecCoin(r.Value).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", filepath.Base(r.File), r.Moniker, r.Validator, value, status)
+	}
+	return tw.Flush()
+}
+
+// sdk errors span several lines, squash them for the reports
+func oneLine(err error) string {
+	return strings.Join(strings.Fields(err.Error()), " ")
+}
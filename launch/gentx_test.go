@@ -0,0 +1,174 @@
+package launch
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/staking"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+const testChainID = "okchain"
+
+// genTxFixture describes a gentx signed by the operator key of its seed
+type genTxFixture struct {
+	operator string // seed of the operator key, which delegates and signs
+	signer   string // seed of the signing key
+	cons     string // seed of the consensus key
+	node     string // seed of the node id
+	file     string // file name, gentx-<node id>.json when empty
+	chainID  string
+	accNum   uint64
+	seq      uint64
+	value    sdk.Coin
+	rate     sdk.Dec
+	fee      auth.StdFee // fee of the encoded tx, signed with the fee of newGenTxFixture
+}
+
+func newGenTxFixture(seed string) genTxFixture {
+	return genTxFixture{
+		operator: seed,
+		signer:   seed,
+		cons:     seed,
+		node:     seed,
+		chainID:  testChainID,
+		value:    sdk.NewCoin("okb", sdk.NewInt(1e8)),
+		rate:     sdk.NewDecWithPrec(1, 1),
+		fee:      auth.NewStdFee(200000, nil),
+	}
+}
+
+func testOperator(seed string) sdk.AccAddress {
+	return sdk.AccAddress(secp256k1.GenPrivKeySecp256k1([]byte(seed)).PubKey().Address())
+}
+
+func testNodeID(seed string) string {
+	return hex.EncodeToString(tmhash.SumTruncated([]byte(seed)))
+}
+
+// sign builds the gentx the way okchaind gentx does
+func (f genTxFixture) sign(t *testing.T) GenTx {
+	t.Helper()
+	msg := staking.NewMsgCreateValidator(sdk.ValAddress(testOperator(f.operator)),
+		ed25519.GenPrivKeyFromSecret([]byte(f.cons)).PubKey(), f.value, staking.NewDescription(f.operator, "", "", ""),
+		staking.NewCommissionMsg(f.rate, sdk.NewDecWithPrec(5, 1), sdk.NewDecWithPrec(1, 2)), sdk.OneInt())
+	memo := testNodeID(f.node) + "@192.168.1.1:26656"
+	msgs := []sdk.Msg{msg}
+	priv := secp256k1.GenPrivKeySecp256k1([]byte(f.signer))
+	sig, err := priv.Sign(auth.StdSignBytes(f.chainID, f.accNum, f.seq, auth.NewStdFee(200000, nil), msgs, memo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bz, err := appCdc.MarshalJSON(auth.NewStdTx(msgs, f.fee, []auth.StdSignature{{PubKey: priv.PubKey(), Signature: sig}}, memo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := f.file
+	if file == "" {
+		file = "gentx-" + testNodeID(f.node) + ".json"
+	}
+	return GenTx{file, bz}
+}
+
+func testGenTxAccounts(seeds ...string) []app.GenesisAccount {
+	var accounts []app.GenesisAccount
+	for _, seed := range seeds {
+		accounts = append(accounts, app.GenesisAccount{Address: testOperator(seed), Coins: newCoins("okb", sdk.NewDec(1))})
+	}
+	return accounts
+}
+
+func TestCheckGenTx(t *testing.T) {
+	signed := func(edit func(*genTxFixture)) func(*testing.T) GenTx {
+		return func(t *testing.T) GenTx {
+			f := newGenTxFixture("alice")
+			edit(&f)
+			return f.sign(t)
+		}
+	}
+	raw := func(file, json string) func(*testing.T) GenTx {
+		return func(*testing.T) GenTx { return GenTx{file, []byte(json)} }
+	}
+
+	cases := []struct {
+		name  string
+		genTx func(*testing.T) GenTx
+		err   string // part of the expected error, empty when valid
+	}{
+		{"valid", signed(func(f *genTxFixture) {}), ""},
+		{"not json", raw("gentx-x.json", "{"), "decoding StdTx"},
+		{"no message", raw("gentx-x.json", `{"type":"auth/StdTx","value":{"msg":[]}}`), "expected a single message"},
+		{"wrong chain id", signed(func(f *genTxFixture) { f.chainID = "other" }), "doesn't verify"},
+		{"account number", signed(func(f *genTxFixture) { f.accNum = 1 }), "doesn't verify"},
+		{"sequence", signed(func(f *genTxFixture) { f.seq = 1 }), "doesn't verify"},
+		{"tampered", signed(func(f *genTxFixture) { f.fee = auth.NewStdFee(300000, nil) }), "doesn't verify"},
+		{"other signer", signed(func(f *genTxFixture) { f.signer = "mallory" }), "doesn't belong to signer"},
+		{"memo", signed(func(f *genTxFixture) { f.file = "gentx-" + testNodeID("bob") + ".json" }), "doesn't match the file name"},
+		{"commission", signed(func(f *genTxFixture) { f.rate = sdk.NewDecWithPrec(6, 1) }), "commission"},
+		{"denom", signed(func(f *genTxFixture) { f.value = sdk.NewCoin("xyz", sdk.NewInt(1e8)) }), "expected the bond denom okb"},
+		{"not funded", signed(func(f *genTxFixture) { f.value = sdk.NewCoin("okb", sdk.NewInt(1e8+1)) }), "insufficient fund"},
+		{"no account", signed(func(f *genTxFixture) { f.operator, f.signer = "bob", "bob" }), "is not a genesis account"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewBuilder(testChainID, time.Time{})
+			b.genTxs = []GenTx{tc.genTx(t)}
+			err := b.CheckGenTxs(testGenTxAccounts("alice"), "okb")
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				r := b.GenTxReports()[0]
+				if r.Moniker != "alice" || r.Peer.ID != testNodeID("alice") || r.ConsPub == "" {
+					t.Errorf("unexpected report %+v", r)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected an error with %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestCheckGenTxsDuplicates(t *testing.T) {
+	first := newGenTxFixture("alice")
+	sameValidator := newGenTxFixture("alice")
+	sameValidator.cons, sameValidator.node = "alice2", "alice2"
+	sameCons := newGenTxFixture("bob")
+	sameCons.cons = "alice"
+	// reuses the pubkey of the rejected duplicate, which must not be recorded
+	reusesRejected := newGenTxFixture("carol")
+	reusesRejected.cons = "alice2"
+	again := newGenTxFixture("alice")
+	again.cons, again.node = "alice3", "alice3"
+
+	b := NewBuilder(testChainID, time.Time{})
+	for _, f := range []genTxFixture{first, sameValidator, sameCons, reusesRejected, again} {
+		b.genTxs = append(b.genTxs, f.sign(t))
+	}
+	if err := b.CheckGenTxs(testGenTxAccounts("alice", "bob", "carol"), "okb"); err == nil {
+		t.Fatal("expected the duplicates to be rejected")
+	}
+
+	firstFile := b.genTxs[0].File
+	want := []string{"", "already created by " + firstFile, "already used by " + firstFile, "", "already created by " + firstFile}
+	for i, r := range b.GenTxReports() {
+		if want[i] == "" {
+			if r.Err != nil {
+				t.Errorf("gentx %d: unexpected error %v", i, r.Err)
+			}
+			continue
+		}
+		if r.Err == nil || !strings.Contains(r.Err.Error(), want[i]) {
+			t.Errorf("gentx %d: expected an error with %q, got %v", i, want[i], r.Err)
+		}
+	}
+}
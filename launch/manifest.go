@@ -0,0 +1,185 @@
+package launch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"text/tabwriter"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// allocation file formats
+const (
+	FormatList     = "list"     // flattened [addr, amt, addr, amt] list
+	FormatObject   = "object"   // {addr: amt} object
+	FormatAccounts = "accounts" // [{"addr": addr, "amount": amt, "lock": lock}] list
+	FormatMultisig = "multisig" // [{"addr": addr, "threshold": k, "pubs": [pub], "amount": amt}] list
+	FormatTable    = "csv"      // csv or tsv rows with a header, see ReadCSVAllocations
+)
+
+// Manifest describes the inputs of a launch.
+// It is written in YAML, JSON being valid YAML works too.
+type Manifest struct {
+	ChainID     string   `yaml:"chain_id"`     // used when no chain id flag is given
+	GenesisTime string   `yaml:"genesis_time"` // RFC3339, used when no genesis time flag is given
+	Tokens      string   `yaml:"tokens"`       // tokens file replacing the template tokens
+	Sources     []Source `yaml:"sources"`
+}
+
+// Source is an allocation file together with what it is expected to hold
+type Source struct {
+	Name      string `yaml:"name"`
+	File      string `yaml:"file"`
+	Format    string `yaml:"format"`
+	Captain   bool   `yaml:"captain"`   // the source holds the captain account
+	Addresses int    `yaml:"addresses"` // expected address count
+	Total     string `yaml:"total"`     // expected total amount of okb
+}
+
+// SourceSummary compares what a source holds with what the manifest expects
+type SourceSummary struct {
+	Source
+	GotAddresses int
+	GotTotal     sdk.Dec
+}
+
+// OK is true when the source holds exactly what is expected
+func (s SourceSummary) OK() bool {
+	total, err := ParseAmount(json.Number(s.Total), denomPrecision)
+	return err == nil && s.GotAddresses == s.Addresses && s.GotTotal.Equal(total)
+}
+
+// ErrSourceMismatch is returned when a source doesn't hold what the manifest expects
+type ErrSourceMismatch struct {
+	SourceSummary
+}
+
+func (e ErrSourceMismatch) Error() string {
+	return fmt.Sprintf("%s (%s): expected %d addresses totalling %s, got %d addresses totalling %v",
+		e.Name, e.File, e.Addresses, e.Total, e.GotAddresses, e.GotTotal)
+}
+
+// LoadManifest reads a launch manifest
+func LoadManifest(file string) (Manifest, error) {
+	var m Manifest
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return m, ErrBadFile{file, err}
+	}
+	if err := yaml.UnmarshalStrict(bz, &m); err != nil {
+		return m, ErrBadFile{file, err}
+	}
+	if m.GenesisTime != "" {
+		if _, err := time.Parse(time.RFC3339, m.GenesisTime); err != nil {
+			return m, ErrBadFile{file, fmt.Errorf("bad genesis_time %q: %v", m.GenesisTime, err)}
+		}
+	}
+
+	captains := 0
+	for _, src := range m.Sources {
+		if src.Name == "" || src.File == "" {
+			return m, ErrBadFile{file, fmt.Errorf("every source needs a name and a file: %+v", src)}
+		}
+		if _, err := ParseAmount(json.Number(src.Total), denomPrecision); err != nil {
+			return m, ErrBadFile{file, fmt.Errorf("source %s: bad total %q", src.Name, src.Total)}
+		}
+		if src.Captain {
+			captains++
+		}
+	}
+	if captains != 1 {
+		return m, ErrBadFile{file, fmt.Errorf("expected exactly one captain source, got %d", captains)}
+	}
+	return m, nil
+}
+
+// AddSource loads the allocation file of a manifest source
+func (b *Builder) AddSource(src Source) error {
+	if src.Captain {
+		if err := b.AddCaptain(src.File); err != nil {
+			return err
+		}
+	} else {
+		switch src.Format {
+		case FormatList:
+			if err := b.AddListFile(src.File); err != nil {
+				return err
+			}
+		case FormatObject:
+			if err := b.AddObjectFile(src.File); err != nil {
+				return err
+			}
+		case FormatAccounts:
+			if err := b.AddAccountsFile(src.File); err != nil {
+				return err
+			}
+		case FormatMultisig:
+			if err := b.AddMultisigFile(src.File); err != nil {
+				return err
+			}
+		case FormatTable:
+			if err := b.AddCSVFile(src.File); err != nil {
+				return err
+			}
+		default:
+			return ErrBadFile{src.File, fmt.Errorf("unknown format %q for source %s", src.Format, src.Name)}
+		}
+	}
+	b.manifestSources = append(b.manifestSources, src)
+	return nil
+}
+
+// SourceSummaries tallies every manifest source added so far
+func (b *Builder) SourceSummaries() []SourceSummary {
+	summaries := make([]SourceSummary, 0, len(b.manifestSources))
+	for _, src := range b.manifestSources {
+		summary := SourceSummary{Source: src, GotTotal: sdk.ZeroDec()}
+		// a csv source can hold several denoms per address
+		addrs := make(map[string]bool)
+		for _, alloc := range b.allocations {
+			if alloc.Source != src.File {
+				continue
+			}
+			addrs[alloc.Address.String()] = true
+			if alloc.Denom == okbDenomination {
+				summary.GotTotal = summary.GotTotal.Add(alloc.Amount)
+			}
+		}
+		summary.GotAddresses = len(addrs)
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// CheckSources checks every manifest source holds the expected
+// address count and total
+func (b *Builder) CheckSources() error {
+	for _, summary := range b.SourceSummaries() {
+		if !summary.OK() {
+			return ErrSourceMismatch{summary}
+		}
+	}
+	return nil
+}
+
+// WriteSourceSummaries prints the summaries as a table
+func WriteSourceSummaries(w io.Writer, summaries []SourceSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tFILE\tADDRS\tEXPECTED\tTOTAL\tEXPECTED\tSTATUS")
+	addrs, total := 0, sdk.ZeroDec()
+	for _, s := range summaries {
+		status := "ok"
+		if !s.OK() {
+			status = "MISMATCH"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%v\t%s\t%s\n", s.Name, s.File, s.GotAddresses, s.Addresses, s.GotTotal, s.Total, status)
+		addrs += s.GotAddresses
+		total = total.Add(s.GotTotal)
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t%d\t\t%v\t\t\n", addrs, total)
+	return tw.Flush()
+}
@@ -0,0 +1,50 @@
+package launch
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	amino "github.com/tendermint/go-amino"
+	crypto "github.com/tendermint/tendermint/crypto"
+	cryptoamino "github.com/tendermint/tendermint/crypto/encoding/amino"
+	cryptomulti "github.com/tendermint/tendermint/crypto/multisig"
+	"github.com/tendermint/tendermint/libs/bech32"
+)
+
+// CheckMultisigAddress checks the address is correct for the sorted pubkey multisig
+func CheckMultisigAddress(k int, pubStrings []string, addr string) error {
+	cdc := amino.NewCodec()
+	cryptoamino.RegisterAmino(cdc)
+	var pubs []crypto.PubKey
+	for _, pubString := range pubStrings {
+		// bech32 decode, then amino decode
+		_, bz, err := bech32.DecodeAndConvert(pubString)
+		if err != nil {
+			return ErrBadBech32{Address: pubString, Err: err}
+		}
+		var pubkey crypto.PubKey
+		err = cdc.UnmarshalBinaryBare(bz, &pubkey)
+		if err != nil {
+			return ErrBadBech32{Address: pubString, Err: err}
+		}
+		pubs = append(pubs, pubkey)
+	}
+
+	if k <= 0 || k > len(pubs) {
+		return fmt.Errorf("multisig %s: threshold %d out of range for %d pubkeys", addr, k, len(pubs))
+	}
+
+	// sort the keys
+	sort.Slice(pubs, func(i, j int) bool {
+		return bytes.Compare(pubs[i].Address(), pubs[j].Address()) < 0
+	})
+
+	pubKey := cryptomulti.NewPubKeyMultisigThreshold(k, pubs)
+	pubKeyAddr := sdk.AccAddress(pubKey.Address()).String()
+	if pubKeyAddr != addr {
+		return ErrMultisigAddress{Address: addr, Computed: pubKeyAddr}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package launch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+)
+
+// account ordering keys, the account numbers follow the order
+const (
+	OrderCaptain = "captain" // the captain first
+	OrderSource  = "source"  // by the order the sources were added in
+	OrderAddress = "address" // by bech32 address
+)
+
+// DefaultAccountOrder gives the captain account number 0, which makes it
+// the owner okchaind falls back on for the tokens without one
+func DefaultAccountOrder() []string {
+	return []string{OrderCaptain, OrderSource, OrderAddress}
+}
+
+// ErrImplicitOwner is returned when the first account, the one okchaind
+// gives the tokens without an owner, isn't the captain
+type ErrImplicitOwner struct {
+	Token   string
+	First   sdk.AccAddress
+	Captain sdk.AccAddress
+}
+
+func (e ErrImplicitOwner) Error() string {
+	return fmt.Sprintf("token %s has no owner and would go to the first account %s instead of the captain %s, "+
+		"put the captain first in the account order or give the token an owner", e.Token, e.First, e.Captain)
+}
+
+// CheckAccountOrder checks the ordering keys, the address always breaks the ties
+func CheckAccountOrder(order []string) error {
+	seen := make(map[string]bool, len(order))
+	for _, key := range order {
+		switch key {
+		case OrderCaptain, OrderSource, OrderAddress:
+		default:
+			return fmt.Errorf("unknown account order %q, expected %s, %s or %s", key, OrderCaptain, OrderSource, OrderAddress)
+		}
+		if seen[key] {
+			return fmt.Errorf("account order %q given twice", key)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// numberAccounts sorts the accounts by the account order and numbers them
+// from 0, so the order okchaind loads them in (by account number) is the
+// order of the genesis file
+func (b *Builder) numberAccounts(accounts []app.GenesisAccount) {
+	// the source of an account is the first one it was allocated in
+	sourceRanks := make(map[string]int)
+	accountSources := make(map[string]int, len(accounts))
+	for _, alloc := range b.allocations {
+		if _, ok := sourceRanks[alloc.Source]; !ok {
+			sourceRanks[alloc.Source] = len(sourceRanks)
+		}
+		addr := alloc.Address.String()
+		if _, ok := accountSources[addr]; !ok {
+			accountSources[addr] = sourceRanks[alloc.Source]
+		}
+	}
+
+	keys := append(append([]string(nil), b.AccountOrder...), OrderAddress)
+	less := func(i, j int) bool {
+		ai, aj := accounts[i].Address, accounts[j].Address
+		for _, key := range keys {
+			switch key {
+			case OrderCaptain:
+				ci, cj := ai.Equals(b.captain), aj.Equals(b.captain)
+				if ci != cj {
+					return ci
+				}
+			case OrderSource:
+				si, sj := accountSources[ai.String()], accountSources[aj.String()]
+				if si != sj {
+					return si < sj
+				}
+			case OrderAddress:
+				if c := strings.Compare(ai.String(), aj.String()); c != 0 {
+					return c < 0
+				}
+			}
+		}
+		return false
+	}
+	sort.SliceStable(accounts, less)
+
+	for i := range accounts {
+		accounts[i].AccountNumber = uint64(i)
+	}
+}
+
+// setImplicitOwners gives the tokens without an owner to the first account,
+// as okchaind would, and checks it is the captain
+func (b *Builder) setImplicitOwners(genesisState *app.GenesisState) error {
+	for i := range genesisState.Token.Info {
+		t := &genesisState.Token.Info[i]
+		if !t.Owner.Empty() {
+			continue
+		}
+		if len(genesisState.Accounts) == 0 || !genesisState.Accounts[0].Address.Equals(b.captain) {
+			var first sdk.AccAddress
+			if len(genesisState.Accounts) > 0 {
+				first = genesisState.Accounts[0].Address
+			}
+			return ErrImplicitOwner{t.Symbol, first, b.captain}
+		}
+		t.Owner = genesisState.Accounts[0].Address
+	}
+	return nil
+}
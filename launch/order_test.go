@@ -0,0 +1,128 @@
+package launch
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/token"
+)
+
+func testAccount(name string) sdk.AccAddress {
+	return sdk.AccAddress([]byte(name + strings.Repeat("_", sdk.AddrLen-len(name))))
+}
+
+// byAddress sorts names by the bech32 address of their account
+func byAddress(names ...string) []string {
+	sort.Slice(names, func(i, j int) bool { return testAccount(names[i]).String() < testAccount(names[j]).String() })
+	return names
+}
+
+func TestNumberAccounts(t *testing.T) {
+	cases := []struct {
+		order []string
+		want  []string
+	}{
+		{DefaultAccountOrder(), append(append([]string{"carol"}, byAddress("bob", "dave")...), "alice")},
+		{[]string{OrderSource, OrderAddress}, append(byAddress("bob", "dave"), byAddress("alice", "carol")...)},
+		{[]string{OrderSource, OrderCaptain}, append(byAddress("bob", "dave"), "carol", "alice")},
+		{[]string{OrderCaptain, OrderAddress}, append([]string{"carol"}, byAddress("alice", "bob", "dave")...)},
+		{[]string{OrderAddress}, byAddress("alice", "bob", "carol", "dave")},
+		{nil, byAddress("alice", "bob", "carol", "dave")},
+	}
+	for _, tc := range cases {
+		t.Run(strings.Join(tc.order, ","), func(t *testing.T) {
+			b := NewBuilder(testChainID, time.Time{})
+			b.AccountOrder = tc.order
+			b.captain = testAccount("carol")
+			// bob's source is the first one he is allocated in
+			for _, alloc := range [][2]string{{"a.json", "dave"}, {"a.json", "bob"}, {"b.json", "carol"}, {"b.json", "bob"}, {"b.json", "alice"}} {
+				b.allocations = append(b.allocations, Allocation{Source: alloc[0], Address: testAccount(alloc[1])})
+			}
+			var accounts []app.GenesisAccount
+			for _, name := range []string{"dave", "carol", "bob", "alice"} {
+				accounts = append(accounts, app.GenesisAccount{Address: testAccount(name)})
+			}
+
+			b.numberAccounts(accounts)
+			for i, acc := range accounts {
+				if !acc.Address.Equals(testAccount(tc.want[i])) {
+					t.Errorf("account %d: expected %s, got %s", i, tc.want[i], acc.Address)
+				}
+				if acc.AccountNumber != uint64(i) {
+					t.Errorf("account %d: got account number %d", i, acc.AccountNumber)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckAccountOrder(t *testing.T) {
+	cases := []struct {
+		order []string
+		ok    bool
+	}{
+		{DefaultAccountOrder(), true},
+		{[]string{OrderAddress}, true},
+		{nil, true},
+		{[]string{"balance"}, false},
+		{[]string{OrderSource, OrderSource}, false},
+	}
+	for _, tc := range cases {
+		t.Run(strings.Join(tc.order, ","), func(t *testing.T) {
+			if err := CheckAccountOrder(tc.order); tc.ok != (err == nil) {
+				t.Errorf("ok=%v, got err %v", tc.ok, err)
+			}
+		})
+	}
+}
+
+func TestSetImplicitOwners(t *testing.T) {
+	captain, other := testAccount("captain"), testAccount("other")
+	cases := []struct {
+		name     string
+		accounts []sdk.AccAddress
+		owner    sdk.AccAddress // of the xyz token, okb has none
+		want     sdk.AccAddress // owner given to okb, nil on ErrImplicitOwner
+		first    sdk.AccAddress // of the error
+	}{
+		{"captain first", []sdk.AccAddress{captain, other}, other, captain, nil},
+		{"other first", []sdk.AccAddress{other, captain}, other, nil, other},
+		{"no accounts", nil, other, nil, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := NewBuilder(testChainID, time.Time{})
+			b.captain = captain
+			state := app.NewDefaultGenesisState()
+			for _, addr := range tc.accounts {
+				state.Accounts = append(state.Accounts, app.GenesisAccount{Address: addr})
+			}
+			state.Token.Info = []token.Token{{Symbol: "okb"}, {Symbol: "xyz", Owner: tc.owner}}
+
+			err := b.setImplicitOwners(&state)
+			if tc.want == nil {
+				e, ok := err.(ErrImplicitOwner)
+				if !ok {
+					t.Fatalf("expected ErrImplicitOwner, got %v", err)
+				}
+				if e.Token != "okb" || !e.First.Equals(tc.first) || !e.Captain.Equals(captain) {
+					t.Errorf("unexpected error %+v", e)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !state.Token.Info[0].Owner.Equals(tc.want) {
+				t.Errorf("expected okb owner %s, got %s", tc.want, state.Token.Info[0].Owner)
+			}
+			if !state.Token.Info[1].Owner.Equals(tc.owner) {
+				t.Errorf("expected the xyz owner %s to be kept, got %s", tc.owner, state.Token.Info[1].Owner)
+			}
+		})
+	}
+}
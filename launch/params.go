@@ -0,0 +1,85 @@
+package launch
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+)
+
+// paramSection is the params of one module of the app state
+type paramSection struct {
+	module string
+	params interface{}
+}
+
+// distr keeps its params at the top of its genesis state
+type distrParams struct {
+	CommunityTax        sdk.Dec `json:"community_tax"`
+	BaseProposerReward  sdk.Dec `json:"base_proposer_reward"`
+	BonusProposerReward sdk.Dec `json:"bonus_proposer_reward"`
+	WithdrawAddrEnabled bool    `json:"withdraw_addr_enabled"`
+}
+
+// paramSections returns the params of every module, in a fixed order
+func paramSections(genesisState app.GenesisState) []paramSection {
+	return []paramSection{
+		{"auth", genesisState.AuthData.Params},
+		{"staking", genesisState.StakingData.Params},
+		{"slashing", genesisState.SlashingData.Params},
+		{"gov", genesisState.GovData.Params},
+		{"mint", genesisState.MintData.Params},
+		{"distr", distrParams{
+			CommunityTax:        genesisState.DistrData.CommunityTax,
+			BaseProposerReward:  genesisState.DistrData.BaseProposerReward,
+			BonusProposerReward: genesisState.DistrData.BonusProposerReward,
+			WithdrawAddrEnabled: genesisState.DistrData.WithdrawAddrEnabled,
+		}},
+		{"order", genesisState.Order.Params},
+		{"token", genesisState.Token.Params},
+	}
+}
+
+// Param is a module parameter with its value in human units
+type Param struct {
+	Module string
+	Name   string // json name
+	Value  string
+	Unit   string // duration, decimal, coins, integer, bool or text
+}
+
+// ModuleParams lists the params of every module of the app state
+func ModuleParams(genesisState app.GenesisState) []Param {
+	var params []Param
+	for _, section := range paramSections(genesisState) {
+		v := reflect.ValueOf(section.params)
+		for i := 0; i < v.NumField(); i++ {
+			name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+			value, unit := describeParam(v.Field(i).Interface())
+			params = append(params, Param{section.module, name, value, unit})
+		}
+	}
+	return params
+}
+
+func describeParam(value interface{}) (string, string) {
+	switch value := value.(type) {
+	case time.Duration:
+		return value.String(), "duration"
+	case sdk.Dec:
+		return value.String(), "decimal"
+	case sdk.DecCoins:
+		return value.String(), "coins"
+	case bool:
+		return fmt.Sprint(value), "bool"
+	case string:
+		return value, "text"
+	case int, int16, int32, int64, uint, uint16, uint32, uint64:
+		return fmt.Sprint(value), "integer"
+	default:
+		return fmt.Sprint(value), ""
+	}
+}
@@ -0,0 +1,108 @@
+package launch
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var reNodeID = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// Peer is a node address as found in a gentx memo: nodeid@ip:port
+type Peer struct {
+	ID   string
+	IP   string
+	Port int
+}
+
+func (p Peer) String() string {
+	return fmt.Sprintf("%s@%s", p.ID, net.JoinHostPort(p.IP, strconv.Itoa(p.Port)))
+}
+
+// ParsePeer parses and checks a nodeid@ip:port address
+func ParsePeer(addr string) (Peer, error) {
+	parts := strings.Split(addr, "@")
+	if len(parts) != 2 {
+		return Peer{}, fmt.Errorf("peer %q is not nodeid@ip:port", addr)
+	}
+	id := parts[0]
+	if !reNodeID.MatchString(id) {
+		return Peer{}, fmt.Errorf("peer %q: node id must be 40 lowercase hex characters", addr)
+	}
+	host, portStr, err := net.SplitHostPort(parts[1])
+	if err != nil {
+		return Peer{}, fmt.Errorf("peer %q: %v", addr, err)
+	}
+	if net.ParseIP(host) == nil {
+		return Peer{}, fmt.Errorf("peer %q: bad ip %q", addr, host)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port <= 0 || port > 65535 {
+		return Peer{}, fmt.Errorf("peer %q: bad port %q", addr, portStr)
+	}
+	return Peer{id, host, port}, nil
+}
+
+// checkGenTxPeer checks the memo of a gentx is the address of the node
+// named in the gentx file name, gentx-<nodeid>.json
+func checkGenTxPeer(file, memo string) (Peer, error) {
+	peer, err := ParsePeer(memo)
+	if err != nil {
+		return peer, err
+	}
+	if name := filepath.Base(file); name != "gentx-"+peer.ID+".json" {
+		return peer, fmt.Errorf("node id %s of the memo doesn't match the file name %s", peer.ID, name)
+	}
+	return peer, nil
+}
+
+// PersistentPeers returns the sorted peers of the checked gentxs
+func (b *Builder) PersistentPeers() []Peer {
+	var peers []Peer
+	for _, r := range b.genTxReports {
+		if r.Err == nil {
+			peers = append(peers, r.Peer)
+		}
+	}
+	sort.Slice(peers, func(i, j int) bool { return peers[i].ID < peers[j].ID })
+	return peers
+}
+
+// JoinPeers formats peers the way tendermint expects them in config.toml
+func JoinPeers(peers []Peer) string {
+	strs := make([]string, 0, len(peers))
+	for _, p := range peers {
+		strs = append(strs, p.String())
+	}
+	return strings.Join(strs, ",")
+}
+
+// WritePeers writes one peer per line
+func WritePeers(w io.Writer, peers []Peer) error {
+	for _, p := range peers {
+		if _, err := fmt.Fprintln(w, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteConfigFragment writes the p2p section of config.toml
+// with the persistent peers and the seeds
+func WriteConfigFragment(w io.Writer, peers, seeds []Peer) error {
+	_, err := fmt.Fprintf(w, `##### peer to peer configuration options #####
+[p2p]
+
+# Comma separated list of seed nodes to connect to
+seeds = "%s"
+
+# Comma separated list of nodes to keep persistent connections to
+persistent_peers = "%s"
+`, JoinPeers(seeds), JoinPeers(peers))
+	return err
+}
@@ -0,0 +1,150 @@
+package launch
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"text/tabwriter"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// PowerThresholds are the limits of the voting power concentration checks
+type PowerThresholds struct {
+	MaxShare    sdk.Dec // warn when a validator holds more of the bonded stake
+	MinHaltSet  int     // warn when fewer validators hold more than 1/3 and can halt the chain
+	MinStartSet int     // warn when fewer validators hold more than 2/3 and can start the chain
+}
+
+// DefaultPowerThresholds flags any validator able to halt the chain alone
+func DefaultPowerThresholds() PowerThresholds {
+	return PowerThresholds{
+		MaxShare:    sdk.NewDecWithPrec(33, 2),
+		MinHaltSet:  3,
+		MinStartSet: 5,
+	}
+}
+
+// ValidatorPower is the bonded stake of a gentx validator
+type ValidatorPower struct {
+	Moniker   string
+	Validator string
+	Value     sdk.Coin
+	Share     *big.Rat // of the total bonded stake
+}
+
+// PowerReport is the distribution of the initial voting power
+type PowerReport struct {
+	Validators []ValidatorPower // by decreasing stake
+	HaltSet    int              // fewest validators holding more than 1/3
+	StartSet   int              // fewest validators holding more than 2/3
+	Warnings   []string
+}
+
+// ErrMaxValidators is returned when the gentxs don't fit in the validator set
+type ErrMaxValidators struct {
+	MaxValidators uint16
+	GenTxs        int
+}
+
+func (e ErrMaxValidators) Error() string {
+	return fmt.Sprintf("staking max_validators is %d but there are %d gentxs, some validators wouldn't be bonded",
+		e.MaxValidators, e.GenTxs)
+}
+
+// PowerReport returns the report of the last CheckPower
+func (b *Builder) PowerReport() *PowerReport {
+	return b.powerReport
+}
+
+// CheckPower computes the share of the bonded stake of every checked gentx
+// and warns about the concentration of the voting power. The validator set
+// must be large enough for every gentx.
+func (b *Builder) CheckPower(maxValidators uint16) error {
+	if int(maxValidators) < len(b.genTxReports) {
+		return ErrMaxValidators{maxValidators, len(b.genTxReports)}
+	}
+
+	report := &PowerReport{}
+	total := sdk.ZeroInt()
+	for _, r := range b.genTxReports {
+		report.Validators = append(report.Validators, ValidatorPower{
+			Moniker: r.Moniker, Validator: r.Validator, Value: r.Value,
+		})
+		total = total.Add(r.Value.Amount)
+	}
+	b.powerReport = report
+	if !total.IsPositive() {
+		report.Warnings = append(report.Warnings, "no bonded stake, the chain can't start")
+		return nil
+	}
+
+	sort.SliceStable(report.Validators, func(i, j int) bool {
+		vi, vj := report.Validators[i], report.Validators[j]
+		if !vi.Value.Amount.Equal(vj.Value.Amount) {
+			return vi.Value.Amount.GT(vj.Value.Amount)
+		}
+		return vi.Validator < vj.Validator
+	})
+
+	maxShare := new(big.Rat).SetFrac(b.PowerThresholds.MaxShare.Int, sdk.NewIntWithDecimal(1, sdk.Precision).BigInt())
+	oneThird, twoThirds := big.NewRat(1, 3), big.NewRat(2, 3)
+	cumulative := new(big.Rat)
+	for i := range report.Validators {
+		v := &report.Validators[i]
+		v.Share = new(big.Rat).SetFrac(v.Value.Amount.BigInt(), total.BigInt())
+		if v.Share.Cmp(maxShare) > 0 {
+			report.Warnings = append(report.Warnings, fmt.Sprintf("%s (%s) holds %s of the stake, more than %s",
+				v.Moniker, v.Validator, formatShare(v.Share), formatShare(maxShare)))
+		}
+
+		cumulative.Add(cumulative, v.Share)
+		if report.HaltSet == 0 && cumulative.Cmp(oneThird) > 0 {
+			report.HaltSet = i + 1
+		}
+		if report.StartSet == 0 && cumulative.Cmp(twoThirds) > 0 {
+			report.StartSet = i + 1
+		}
+	}
+
+	if report.HaltSet < b.PowerThresholds.MinHaltSet {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d validator(s) hold more than 1/3 and can halt the chain, expected at least %d",
+			report.HaltSet, b.PowerThresholds.MinHaltSet))
+	}
+	if report.StartSet < b.PowerThresholds.MinStartSet {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d validator(s) hold more than 2/3 and can start the chain, expected at least %d",
+			report.StartSet, b.PowerThresholds.MinStartSet))
+	}
+	return nil
+}
+
+func formatShare(share *big.Rat) string {
+	return new(big.Rat).Mul(share, big.NewRat(100, 1)).FloatString(2) + "%"
+}
+
+// WritePowerReport prints the voting power distribution and its warnings
+func WritePowerReport(w io.Writer, report *PowerReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "MONIKER\tVALIDATOR\tSELF DELEGATION\tSHARE\tCUMULATIVE")
+	cumulative := new(big.Rat)
+	for _, v := range report.Validators {
+		if v.Share == nil {
+			continue
+		}
+		cumulative.Add(cumulative, v.Share)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			v.Moniker, v.Validator, toDecCoin(v.Value), formatShare(v.Share), formatShare(cumulative))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "validators to halt (> 1/3) ", report.HaltSet)
+	fmt.Fprintln(w, "validators to start (> 2/3)", report.StartSet)
+	for _, warning := range report.Warnings {
+		if _, err := fmt.Fprintln(w, "WARNING:", warning); err != nil {
+			return err
+		}
+	}
+	return nil
+}
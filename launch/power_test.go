@@ -0,0 +1,92 @@
+package launch
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// powerBuilder returns a builder with a checked gentx per stake, named val0, val1...
+func powerBuilder(stakes ...int64) *Builder {
+	b := NewBuilder(testChainID, time.Time{})
+	b.PowerThresholds = DefaultPowerThresholds()
+	for i, stake := range stakes {
+		name := "val" + string(rune('0'+i))
+		b.genTxReports = append(b.genTxReports, GenTxReport{
+			Moniker: name, Validator: name, Value: sdk.NewCoin("okb", sdk.NewInt(stake)),
+		})
+	}
+	return b
+}
+
+func TestCheckPower(t *testing.T) {
+	cases := []struct {
+		name     string
+		stakes   []int64
+		order    []string // validators by decreasing stake
+		shares   []string // in order
+		haltSet  int
+		startSet int
+		warnings []string // part of each expected warning
+	}{
+		{"single validator", []int64{10}, []string{"val0"}, []string{"1/1"}, 1, 1,
+			[]string{"val0 (val0) holds 100.00%", "can halt", "can start"}},
+		{"ties", []int64{5, 5, 5}, []string{"val0", "val1", "val2"}, []string{"1/3", "1/3", "1/3"}, 2, 3,
+			[]string{"val0 (val0) holds 33.33%", "val1 (val1) holds 33.33%", "val2 (val2) holds 33.33%", "2 validator(s) hold more than 1/3", "3 validator(s) hold more than 2/3"}},
+		{"exact thirds", []int64{1, 1, 1, 1, 1, 1}, []string{"val0", "val1", "val2", "val3", "val4", "val5"},
+			[]string{"1/6", "1/6", "1/6", "1/6", "1/6", "1/6"}, 3, 5, nil},
+		{"spread", []int64{1, 3, 4, 2}, []string{"val2", "val1", "val3", "val0"}, []string{"2/5", "3/10", "1/5", "1/10"}, 1, 2,
+			[]string{"val2 (val2) holds 40.00% of the stake, more than 33.00%", "1 validator(s) hold more than 1/3", "2 validator(s) hold more than 2/3"}},
+		{"no stake", []int64{0, 0}, []string{"val0", "val1"}, []string{"", ""}, 0, 0, []string{"no bonded stake"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := powerBuilder(tc.stakes...)
+			if err := b.CheckPower(100); err != nil {
+				t.Fatal(err)
+			}
+			report := b.PowerReport()
+			if report.HaltSet != tc.haltSet || report.StartSet != tc.startSet {
+				t.Errorf("expected halt set %d and start set %d, got %d and %d",
+					tc.haltSet, tc.startSet, report.HaltSet, report.StartSet)
+			}
+			for i, v := range report.Validators {
+				if v.Validator != tc.order[i] {
+					t.Errorf("validator %d: expected %s, got %s", i, tc.order[i], v.Validator)
+				}
+				if tc.shares[i] == "" {
+					if v.Share != nil {
+						t.Errorf("validator %d: unexpected share %v", i, v.Share)
+					}
+					continue
+				}
+				want, _ := new(big.Rat).SetString(tc.shares[i])
+				if v.Share == nil || v.Share.Cmp(want) != 0 {
+					t.Errorf("validator %d: expected share %s, got %v", i, tc.shares[i], v.Share)
+				}
+			}
+			if len(report.Warnings) != len(tc.warnings) {
+				t.Fatalf("expected %d warnings, got %q", len(tc.warnings), report.Warnings)
+			}
+			for i, w := range tc.warnings {
+				if !strings.Contains(report.Warnings[i], w) {
+					t.Errorf("warning %d: expected %q, got %q", i, w, report.Warnings[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPowerMaxValidators(t *testing.T) {
+	b := powerBuilder(1, 1, 1)
+	if err := b.CheckPower(3); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	err := b.CheckPower(2)
+	if e, ok := err.(ErrMaxValidators); !ok || e.MaxValidators != 2 || e.GenTxs != 3 {
+		t.Errorf("expected ErrMaxValidators, got %v", err)
+	}
+}
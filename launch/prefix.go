@@ -0,0 +1,136 @@
+package launch
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/bech32"
+)
+
+// bech32 hrp suffixes of the accounts, validators and consensus nodes
+var prefixSuffixes = []string{
+	"",
+	sdk.PrefixPublic,
+	sdk.PrefixValidator + sdk.PrefixOperator,
+	sdk.PrefixValidator + sdk.PrefixOperator + sdk.PrefixPublic,
+	sdk.PrefixValidator + sdk.PrefixConsensus,
+	sdk.PrefixValidator + sdk.PrefixConsensus + sdk.PrefixPublic,
+}
+
+// a quoted lowercase bech32 string: hrp, separator, data in the bech32 charset
+var reBech32 = regexp.MustCompile(`"([a-z]+1[qpzry9x8gf2tvdw0s3jn54khce6mua7l]{6,})"`)
+
+// PrefixMismatch is a bech32 string that doesn't use the chain prefixes
+type PrefixMismatch struct {
+	File   string
+	Line   int
+	Bech32 string
+	HRP    string
+}
+
+func (m PrefixMismatch) String() string {
+	return fmt.Sprintf("%s:%d: %s uses prefix %q", m.File, m.Line, m.Bech32, m.HRP)
+}
+
+// ErrPrefixMismatches is returned when inputs hold bech32 strings of another chain
+type ErrPrefixMismatches struct {
+	Mismatches []PrefixMismatch
+}
+
+func (e ErrPrefixMismatches) Error() string {
+	lines := make([]string, 0, len(e.Mismatches))
+	for _, m := range e.Mismatches {
+		lines = append(lines, m.String())
+	}
+	return fmt.Sprintf("%d bech32 string(s) don't use the %s prefixes, see convert-prefix:\n  %s",
+		len(e.Mismatches), sdk.Bech32MainPrefix, strings.Join(lines, "\n  "))
+}
+
+// ChainPrefixes returns every bech32 hrp derived from a main prefix
+func ChainPrefixes(main string) []string {
+	hrps := make([]string, 0, len(prefixSuffixes))
+	for _, suffix := range prefixSuffixes {
+		hrps = append(hrps, main+suffix)
+	}
+	return hrps
+}
+
+// FindPrefixMismatches lists the valid bech32 strings of a file whose prefix
+// isn't one of the chain prefixes
+func FindPrefixMismatches(file string) ([]PrefixMismatch, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+
+	allowed := make(map[string]bool)
+	for _, hrp := range ChainPrefixes(sdk.Bech32MainPrefix) {
+		allowed[hrp] = true
+	}
+
+	var mismatches []PrefixMismatch
+	for _, loc := range reBech32.FindAllSubmatchIndex(bz, -1) {
+		str := string(bz[loc[2]:loc[3]])
+		hrp, _, err := bech32.DecodeAndConvert(str)
+		if err != nil || allowed[hrp] {
+			// not bech32 after all, or fine
+			continue
+		}
+		line := bytes.Count(bz[:loc[2]], []byte("\n")) + 1
+		mismatches = append(mismatches, PrefixMismatch{file, line, str, hrp})
+	}
+	return mismatches, nil
+}
+
+// CheckPrefixes checks every file only uses the chain bech32 prefixes
+func CheckPrefixes(files []string) error {
+	var mismatches []PrefixMismatch
+	for _, file := range files {
+		found, err := FindPrefixMismatches(file)
+		if err != nil {
+			return err
+		}
+		mismatches = append(mismatches, found...)
+	}
+	if len(mismatches) > 0 {
+		return ErrPrefixMismatches{mismatches}
+	}
+	return nil
+}
+
+// ConvertPrefix re-encodes every bech32 string of bz using the from main
+// prefix (and its pub/valoper/valcons variants) with the to main prefix.
+// The rest of the document is left untouched. It returns the number of
+// strings converted.
+func ConvertPrefix(bz []byte, from, to string) ([]byte, int, error) {
+	hrps := make(map[string]string)
+	for _, suffix := range prefixSuffixes {
+		hrps[from+suffix] = to + suffix
+	}
+
+	var convErr error
+	converted := 0
+	out := reBech32.ReplaceAllFunc(bz, func(quoted []byte) []byte {
+		str := string(quoted[1 : len(quoted)-1])
+		hrp, data, err := bech32.DecodeAndConvert(str)
+		if err != nil {
+			return quoted
+		}
+		newHrp, ok := hrps[hrp]
+		if !ok {
+			return quoted
+		}
+		newStr, err := bech32.ConvertAndEncode(newHrp, data)
+		if err != nil {
+			convErr = fmt.Errorf("converting %s: %v", str, err)
+			return quoted
+		}
+		converted++
+		return []byte(`"` + newStr + `"`)
+	})
+	return out, converted, convErr
+}
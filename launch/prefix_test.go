@@ -0,0 +1,102 @@
+package launch
+
+import (
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/libs/bech32"
+)
+
+func testBech32(t *testing.T, hrp string, data string) string {
+	t.Helper()
+	str, err := bech32.ConvertAndEncode(hrp, []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return str
+}
+
+func TestConvertPrefix(t *testing.T) {
+	data := "alice_______________"
+	cases := []struct {
+		name      string
+		in        string
+		want      string
+		converted int
+	}{
+		{"account", testBech32(t, "cosmos", data), testBech32(t, "okchain", data), 1},
+		{"account pubkey", testBech32(t, "cosmospub", data), testBech32(t, "okchainpub", data), 1},
+		{"operator", testBech32(t, "cosmosvaloper", data), testBech32(t, "okchainvaloper", data), 1},
+		{"operator pubkey", testBech32(t, "cosmosvaloperpub", data), testBech32(t, "okchainvaloperpub", data), 1},
+		{"consensus", testBech32(t, "cosmosvalcons", data), testBech32(t, "okchainvalcons", data), 1},
+		{"consensus pubkey", testBech32(t, "cosmosvalconspub", data), testBech32(t, "okchainvalconspub", data), 1},
+		{"other chain", testBech32(t, "terra", data), testBech32(t, "terra", data), 0},
+		{"already converted", testBech32(t, "okchain", data), testBech32(t, "okchain", data), 0},
+		{"bad checksum", "cosmos1qqqqqqqqqq", "cosmos1qqqqqqqqqq", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, n, err := ConvertPrefix([]byte(`{"address": "`+tc.in+`"}`), "cosmos", "okchain")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != tc.converted {
+				t.Errorf("expected %d conversions, got %d", tc.converted, n)
+			}
+			if want := `{"address": "` + tc.want + `"}`; string(out) != want {
+				t.Errorf("expected %s, got %s", want, out)
+			}
+		})
+	}
+}
+
+func TestConvertPrefixKeepsDocument(t *testing.T) {
+	// unquoted or embedded strings and the layout are left alone
+	account := testBech32(t, "cosmos", "alice_______________")
+	in := "{\n  \"memo\": \"sent by " + account + "\",\n  \"to\": \"" + account + "\"\n}\n"
+	out, n, err := ConvertPrefix([]byte(in), "cosmos", "okchain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(in, `"`+account+`"`, `"`+testBech32(t, "okchain", "alice_______________")+`"`, 1)
+	if n != 1 || string(out) != want {
+		t.Errorf("expected 1 conversion to\n%s\ngot %d to\n%s", want, n, out)
+	}
+
+	back, _, err := ConvertPrefix(out, "okchain", "cosmos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(back) != in {
+		t.Errorf("expected the round trip to give\n%s\ngot\n%s", in, back)
+	}
+}
+
+func TestCheckPrefixes(t *testing.T) {
+	data := "alice_______________"
+	clean := writeTestFile(t, "clean.json", `{"address": "`+testBech32(t, sdk.Bech32MainPrefix, data)+`"}`)
+	foreign := writeTestFile(t, "foreign.json", "{\n  \"address\": \""+testBech32(t, sdk.Bech32MainPrefix, data)+
+		"\",\n  \"validator\": \""+testBech32(t, "cosmosvaloper", data)+"\"\n}")
+
+	if err := CheckPrefixes([]string{clean}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	err := CheckPrefixes([]string{clean, foreign})
+	mismatches, ok := err.(ErrPrefixMismatches)
+	if !ok {
+		t.Fatalf("expected ErrPrefixMismatches, got %v", err)
+	}
+	if len(mismatches.Mismatches) != 1 {
+		t.Fatalf("expected 1 mismatch, got %v", mismatches.Mismatches)
+	}
+	m := mismatches.Mismatches[0]
+	if m.File != foreign || m.Line != 3 || m.HRP != "cosmosvaloper" {
+		t.Errorf("unexpected mismatch %+v", m)
+	}
+
+	if _, ok := CheckPrefixes([]string{clean + ".missing"}).(ErrBadFile); !ok {
+		t.Error("expected ErrBadFile for a missing file")
+	}
+}
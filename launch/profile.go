@@ -0,0 +1,174 @@
+package launch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ok-chain/okchain/app"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Profile overlays module params on the genesis template, e.g. the
+// durations and deposits of a mainnet
+type Profile struct {
+	File   string
+	Params map[string]map[string]interface{} // by module then param name
+}
+
+// LoadProfile reads a YAML profile of params addressed by module and name:
+//
+//	staking:
+//	  unbonding_time: 21d
+//
+// Values are written as in the amino json of the template or in human
+// units, see paramJSON.
+func LoadProfile(file string) (Profile, error) {
+	p := Profile{File: file, Params: make(map[string]map[string]interface{})}
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return p, ErrBadFile{file, err}
+	}
+	var modules map[string]map[string]interface{}
+	if err := yaml.UnmarshalStrict(bz, &modules); err != nil {
+		return p, ErrBadFile{file, err}
+	}
+	for module, params := range modules {
+		p.Params[module] = make(map[string]interface{}, len(params))
+		for name, value := range params {
+			p.Params[module][name] = jsonValue(value)
+		}
+	}
+	return p, nil
+}
+
+// jsonValue turns the nested maps of yaml.v2 into json objects
+func jsonValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			object[fmt.Sprint(k)] = jsonValue(v)
+		}
+		return object
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, v := range value {
+			list[i] = jsonValue(v)
+		}
+		return list
+	default:
+		return value
+	}
+}
+
+// Apply overlays the profile on the app state. Every value is converted to
+// the amino json of its param and checked against its type before anything
+// is changed.
+func (p Profile) Apply(genesisState *app.GenesisState) error {
+	types := paramTypes(*genesisState)
+	values := make(map[string]map[string]json.RawMessage, len(p.Params))
+	for _, key := range p.names() {
+		module, name := key[0], key[1]
+		moduleTypes, ok := types[module]
+		if !ok {
+			return ErrBadFile{p.File, fmt.Errorf("unknown module %q", module)}
+		}
+		t, ok := moduleTypes[name]
+		if !ok {
+			return ErrBadFile{p.File, fmt.Errorf("unknown %s param %q", module, name)}
+		}
+		raw, err := paramJSON(t, amountParams[module+"."+name], p.Params[module][name])
+		if err != nil {
+			return ErrBadFile{p.File, fmt.Errorf("%s.%s: %v", module, name, err)}
+		}
+		if err := appCdc.UnmarshalJSON(raw, reflect.New(t).Interface()); err != nil {
+			return ErrBadFile{p.File, fmt.Errorf("%s.%s: %s is not a valid %v: %v", module, name, raw, t, oneLine(err))}
+		}
+		if values[module] == nil {
+			values[module] = make(map[string]json.RawMessage)
+		}
+		values[module][name] = raw
+	}
+
+	// overlay the amino json of the app state and decode it back
+	bz, err := appCdc.MarshalJSON(*genesisState)
+	if err != nil {
+		return err
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(bz, &doc); err != nil {
+		return err
+	}
+	for module, params := range values {
+		var moduleDoc map[string]json.RawMessage
+		if err := json.Unmarshal(doc[module], &moduleDoc); err != nil {
+			return err
+		}
+		// distr keeps its params at the top of its genesis state
+		if module == "distr" {
+			overlayJSON(moduleDoc, params)
+		} else {
+			var section map[string]json.RawMessage
+			if err := json.Unmarshal(moduleDoc["params"], &section); err != nil {
+				return err
+			}
+			overlayJSON(section, params)
+			if moduleDoc["params"], err = json.Marshal(section); err != nil {
+				return err
+			}
+		}
+		if doc[module], err = json.Marshal(moduleDoc); err != nil {
+			return err
+		}
+	}
+	if bz, err = json.Marshal(doc); err != nil {
+		return err
+	}
+	var merged app.GenesisState
+	if err := appCdc.UnmarshalJSON(bz, &merged); err != nil {
+		return ErrBadFile{p.File, err}
+	}
+	*genesisState = merged
+	return nil
+}
+
+func overlayJSON(object, values map[string]json.RawMessage) {
+	for name, value := range values {
+		object[name] = value
+	}
+}
+
+// paramTypes returns the go type of every param, by module then json name
+func paramTypes(genesisState app.GenesisState) map[string]map[string]reflect.Type {
+	types := make(map[string]map[string]reflect.Type)
+	for _, section := range paramSections(genesisState) {
+		t := reflect.TypeOf(section.params)
+		types[section.module] = make(map[string]reflect.Type, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			types[section.module][name] = t.Field(i).Type
+		}
+	}
+	return types
+}
+
+// names returns the params of the profile as sorted module, name pairs
+func (p Profile) names() [][2]string {
+	var names [][2]string
+	for module, params := range p.Params {
+		for name := range params {
+			names = append(names, [2]string{module, name})
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if names[i][0] != names[j][0] {
+			return names[i][0] < names[j][0]
+		}
+		return names[i][1] < names[j][1]
+	})
+	return names
+}
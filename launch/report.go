@@ -0,0 +1,414 @@
+package launch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// report formats
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+)
+
+// Table is one section of a report
+type Table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// GenesisReport renders a genesis file for the allocation sign-off:
+// supply, top holders, vesting, validators and params
+func GenesisReport(genesisDoc *tmtypes.GenesisDoc, genesisState app.GenesisState, top int) []Table {
+	return []Table{
+		{
+			Title:  "Genesis",
+			Header: []string{"FIELD", "VALUE"},
+			Rows: [][]string{
+				{"chain id", genesisDoc.ChainID},
+				{"genesis time", genesisDoc.GenesisTime.UTC().Format(time.RFC3339)},
+				{"accounts", fmt.Sprint(len(genesisState.Accounts))},
+				{"gentxs", fmt.Sprint(len(genesisState.GenTxs))},
+			},
+		},
+		supplyTable(genesisState),
+		holdersTable(genesisState.Accounts, top),
+		vestingTable(genesisState.Accounts),
+		unlockTable(genesisDoc.GenesisTime, genesisState.Accounts),
+		validatorsTable(genesisState.GenTxs),
+		paramsTable(genesisState),
+	}
+}
+
+// AllocationReport lists every allocation with the file and csv line it
+// comes from, then the totals per category
+func AllocationReport(allocs []Allocation) []Table {
+	rows := Table{Title: "Allocations", Header: []string{"SOURCE", "ADDRESS", "DENOM", "AMOUNT", "VESTING", "CATEGORY", "LABEL"}}
+	type categoryDenom struct{ category, denom string }
+	totals := make(map[categoryDenom]sdk.Dec)
+	holders := make(map[categoryDenom]int)
+	for _, alloc := range allocs {
+		vesting := ""
+		if alloc.Lock != nil {
+			vesting = alloc.Vesting.String()
+		}
+		rows.Rows = append(rows.Rows, []string{alloc.Position(), alloc.Address.String(), alloc.Denom,
+			alloc.Amount.String(), vesting, alloc.Category, alloc.Label})
+
+		key := categoryDenom{alloc.Category, alloc.Denom}
+		if total, ok := totals[key]; ok {
+			totals[key] = total.Add(alloc.Amount)
+		} else {
+			totals[key] = alloc.Amount
+		}
+		holders[key]++
+	}
+
+	keys := make([]categoryDenom, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].category != keys[j].category {
+			return keys[i].category < keys[j].category
+		}
+		return keys[i].denom < keys[j].denom
+	})
+	categories := Table{Title: "Categories", Header: []string{"CATEGORY", "DENOM", "ALLOCATIONS", "TOTAL"}}
+	for _, key := range keys {
+		category := key.category
+		if category == "" {
+			category = "-"
+		}
+		categories.Rows = append(categories.Rows, []string{category, key.denom, fmt.Sprint(holders[key]), totals[key].String()})
+	}
+	return []Table{rows, categories}
+}
+
+// denomTotals sums the coins of the accounts per denom
+func denomTotals(accounts []app.GenesisAccount) (map[string]sdk.Dec, []string) {
+	totals := make(map[string]sdk.Dec)
+	for _, acc := range accounts {
+		for _, coin := range acc.Coins {
+			if total, ok := totals[coin.Denom]; ok {
+				totals[coin.Denom] = total.Add(coin.Amount)
+			} else {
+				totals[coin.Denom] = coin.Amount
+			}
+		}
+	}
+	denoms := make([]string, 0, len(totals))
+	for denom := range totals {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+	return totals, denoms
+}
+
+func supplyTable(genesisState app.GenesisState) Table {
+	t := Table{Title: "Supply", Header: []string{"DENOM", "HOLDERS", "TOTAL", "TOKEN SUPPLY", "VESTING"}}
+	totals, denoms := denomTotals(genesisState.Accounts)
+
+	supplies := make(map[string]string)
+	for _, info := range genesisState.Token.Info {
+		supplies[info.Symbol] = fmt.Sprint(info.TotalSupply)
+	}
+	holders := make(map[string]int)
+	vesting := make(map[string]sdk.Int)
+	for _, acc := range genesisState.Accounts {
+		for _, coin := range acc.Coins {
+			if coin.Amount.IsPositive() {
+				holders[coin.Denom]++
+			}
+		}
+		for _, coin := range acc.OriginalVesting {
+			if v, ok := vesting[coin.Denom]; ok {
+				vesting[coin.Denom] = v.Add(coin.Amount)
+			} else {
+				vesting[coin.Denom] = coin.Amount
+			}
+		}
+	}
+
+	for _, denom := range denoms {
+		locked := ""
+		if v, ok := vesting[denom]; ok {
+			locked = toDecCoin(sdk.NewCoin(denom, v)).Amount.String()
+		}
+		t.Rows = append(t.Rows, []string{
+			denom, fmt.Sprint(holders[denom]), totals[denom].String(), supplies[denom], locked,
+		})
+	}
+	return t
+}
+
+func holdersTable(accounts []app.GenesisAccount, top int) Table {
+	t := Table{Title: "Top holders", Header: []string{"DENOM", "RANK", "ACCOUNT", "AMOUNT", "SHARE"}}
+	totals, denoms := denomTotals(accounts)
+	for _, denom := range denoms {
+		var holders []app.GenesisAccount
+		for _, acc := range accounts {
+			if acc.Coins.AmountOf(denom).IsPositive() {
+				holders = append(holders, acc)
+			}
+		}
+		sort.SliceStable(holders, func(i, j int) bool {
+			a, b := holders[i].Coins.AmountOf(denom), holders[j].Coins.AmountOf(denom)
+			if !a.Equal(b) {
+				return a.GT(b)
+			}
+			return holders[i].Address.String() < holders[j].Address.String()
+		})
+		if top > 0 && len(holders) > top {
+			holders = holders[:top]
+		}
+		for i, acc := range holders {
+			amount := acc.Coins.AmountOf(denom)
+			t.Rows = append(t.Rows, []string{
+				denom, fmt.Sprint(i + 1), acc.Address.String(), amount.String(), percent(amount.Int, totals[denom].Int),
+			})
+		}
+	}
+	return t
+}
+
+func vestingTable(accounts []app.GenesisAccount) Table {
+	t := Table{Title: "Vesting", Header: []string{"ACCOUNT", "VESTING", "SCHEDULE", "START", "END"}}
+	for _, acc := range accounts {
+		if acc.OriginalVesting.IsZero() {
+			continue
+		}
+		schedule, start := "delayed", ""
+		if acc.StartTime != 0 {
+			schedule, start = "continuous", formatUnix(acc.StartTime)
+		}
+		for _, coin := range acc.OriginalVesting {
+			t.Rows = append(t.Rows, []string{
+				acc.Address.String(), toDecCoin(coin).String(), schedule, start, formatUnix(acc.EndTime),
+			})
+		}
+	}
+	return t
+}
+
+// unlockTable is the calendar of the vesting: what unlocks month by month
+func unlockTable(genesisTime time.Time, accounts []app.GenesisAccount) Table {
+	t := Table{Title: "Unlock calendar", Header: []string{"MONTH", "DENOM", "UNLOCKED", "CUMULATIVE", "STILL LOCKED"}}
+
+	var last int64
+	totals := make(map[string]sdk.Int)
+	for _, acc := range accounts {
+		if acc.OriginalVesting.IsZero() {
+			continue
+		}
+		if acc.EndTime > last {
+			last = acc.EndTime
+		}
+		for _, coin := range acc.OriginalVesting {
+			if total, ok := totals[coin.Denom]; ok {
+				totals[coin.Denom] = total.Add(coin.Amount)
+			} else {
+				totals[coin.Denom] = coin.Amount
+			}
+		}
+	}
+	denoms := make([]string, 0, len(totals))
+	for denom := range totals {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+
+	// unlocked at the start of each month, until everything is
+	month := time.Date(genesisTime.Year(), genesisTime.Month(), 1, 0, 0, 0, 0, time.UTC)
+	prev := make(map[string]sdk.Int)
+	for _, denom := range denoms {
+		prev[denom] = sdk.ZeroInt()
+	}
+	for len(denoms) > 0 && month.Unix() <= last {
+		next := month.AddDate(0, 1, 0)
+		for _, denom := range denoms {
+			unlocked := sdk.ZeroInt()
+			for _, acc := range accounts {
+				unlocked = unlocked.Add(vestedUnits(acc, denom, next.Unix()))
+			}
+			if unlocked.GT(prev[denom]) {
+				t.Rows = append(t.Rows, []string{
+					month.Format("2006-01"), denom,
+					toDecCoin(sdk.NewCoin(denom, unlocked.Sub(prev[denom]))).Amount.String(),
+					toDecCoin(sdk.NewCoin(denom, unlocked)).Amount.String(),
+					toDecCoin(sdk.NewCoin(denom, totals[denom].Sub(unlocked))).Amount.String(),
+				})
+			}
+			prev[denom] = unlocked
+		}
+		month = next
+	}
+	return t
+}
+
+// vestedUnits is the part of the original vesting of a denom unlocked at a
+// unix time, computed like the vesting accounts do
+func vestedUnits(acc app.GenesisAccount, denom string, at int64) sdk.Int {
+	orig := acc.OriginalVesting.AmountOf(denom)
+	switch {
+	case orig.IsZero():
+		return orig
+	case at >= acc.EndTime:
+		return orig
+	case acc.StartTime == 0 || at <= acc.StartTime:
+		return sdk.ZeroInt()
+	default:
+		return orig.MulRaw(at - acc.StartTime).QuoRaw(acc.EndTime - acc.StartTime)
+	}
+}
+
+func validatorsTable(genTxs []json.RawMessage) Table {
+	t := Table{
+		Title:  "Validators",
+		Header: []string{"MONIKER", "OPERATOR", "COMMISSION", "MAX", "MAX CHANGE", "SELF DELEGATION", "POWER", "SHARE"},
+	}
+	type validator struct {
+		moniker, operator string
+		rate, max, change sdk.Dec
+		value             sdk.Coin
+	}
+	var validators []validator
+	total := sdk.ZeroInt()
+	for _, genTx := range genTxs {
+		_, msg, err := DecodeGenTx(genTx)
+		if err != nil {
+			// reported by the gentx checks
+			continue
+		}
+		validators = append(validators, validator{
+			msg.Description.Moniker, msg.ValidatorAddress.String(),
+			msg.Commission.Rate, msg.Commission.MaxRate, msg.Commission.MaxChangeRate,
+			msg.Value,
+		})
+		total = total.Add(msg.Value.Amount)
+	}
+	sort.SliceStable(validators, func(i, j int) bool {
+		if !validators[i].value.Amount.Equal(validators[j].value.Amount) {
+			return validators[i].value.Amount.GT(validators[j].value.Amount)
+		}
+		return validators[i].operator < validators[j].operator
+	})
+	for _, v := range validators {
+		t.Rows = append(t.Rows, []string{
+			v.moniker, v.operator, v.rate.String(), v.max.String(), v.change.String(),
+			toDecCoin(v.value).String(), fmt.Sprint(tendermintPower(v.value.Amount)),
+			percent(v.value.Amount.BigInt(), total.BigInt()),
+		})
+	}
+	return t
+}
+
+func paramsTable(genesisState app.GenesisState) Table {
+	t := Table{Title: "Params", Header: []string{"MODULE", "PARAM", "VALUE", "UNIT"}}
+	for _, p := range ModuleParams(genesisState) {
+		t.Rows = append(t.Rows, []string{p.Module, p.Name, p.Value, p.Unit})
+	}
+	return t
+}
+
+// percent formats part/total with two decimals
+func percent(part, total *big.Int) string {
+	if total.Sign() == 0 {
+		return ""
+	}
+	r := new(big.Rat).SetFrac(part, total)
+	r.Mul(r, big.NewRat(100, 1))
+	return r.FloatString(2) + "%"
+}
+
+func formatUnix(t int64) string {
+	return time.Unix(t, 0).UTC().Format(time.RFC3339)
+}
+
+// WriteTables prints the tables as text, markdown or csv
+func WriteTables(w io.Writer, tables []Table, format string) error {
+	switch format {
+	case FormatText:
+		return writeTextTables(w, tables)
+	case FormatMarkdown:
+		return writeMarkdownTables(w, tables)
+	case FormatCSV:
+		return writeCSVTables(w, tables)
+	default:
+		return fmt.Errorf("unknown format %q, expected %s, %s or %s", format, FormatText, FormatMarkdown, FormatCSV)
+	}
+}
+
+func writeTextTables(w io.Writer, tables []Table) error {
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s\n-----------\n", t.Title)
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.Header, "\t"))
+		for _, row := range t.Rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMarkdownTables(w io.Writer, tables []Table) error {
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	line := func(cells []string) string {
+		escaped := make([]string, 0, len(cells))
+		for _, c := range cells {
+			escaped = append(escaped, cell.Replace(c))
+		}
+		return "| " + strings.Join(escaped, " | ") + " |"
+	}
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "## %s\n\n", t.Title)
+		fmt.Fprintln(w, line(t.Header))
+		sep := make([]string, len(t.Header))
+		for j := range sep {
+			sep[j] = "---"
+		}
+		fmt.Fprintln(w, line(sep))
+		for _, row := range t.Rows {
+			if _, err := fmt.Fprintln(w, line(row)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// the csv holds every table one after the other: a row with the title,
+// the header, the rows and an empty row
+func writeCSVTables(w io.Writer, tables []Table) error {
+	cw := csv.NewWriter(w)
+	for _, t := range tables {
+		cw.Write([]string{t.Title})
+		cw.Write(t.Header)
+		cw.WriteAll(t.Rows)
+		cw.Write(nil)
+	}
+	cw.Flush()
+	return cw.Error()
+}
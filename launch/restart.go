@@ -0,0 +1,329 @@
+package launch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/slashing"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/gov"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// RestartConfig is the new network a halted chain restarts as
+type RestartConfig struct {
+	ChainID     string
+	GenesisTime time.Time
+	Template    string // module sections the export leaves empty come from it
+	GenTxPath   string // validators of the new network when the export has none
+	AllowUnheld bool   // accept a token supply no account holds
+}
+
+// RestartReport tells what a restart carried over from the export and
+// what it took from the template
+type RestartReport struct {
+	ExportedChainID string
+	Accounts        int
+	Validators      int
+	Delegations     int
+	Unbonding       int
+	Redelegations   int
+	Proposals       int
+	GenTxs          int
+	DroppedSlashes  int
+	Filled          []string // module sections taken from the template
+	ImplicitOwners  []string // tokens without an owner, given to the first account
+	FirstAccount    sdk.AccAddress
+	Unheld          sdk.DecCoins // token supply no account holds, bonded when the export has no staking state
+}
+
+// ErrNoValidators is returned when the restarted chain would have no validator
+type ErrNoValidators struct {
+	File string
+}
+
+func (e ErrNoValidators) Error() string {
+	return fmt.Sprintf("%s has no staking validators, okchaind exports only the accounts: "+
+		"pass the gentxs of the new validators", e.File)
+}
+
+// ErrUnheldSupply is returned when the accounts of an export without staking
+// state don't hold the whole token supply, the bonded tokens being lost
+type ErrUnheldSupply struct {
+	Unheld sdk.DecCoins
+}
+
+func (e ErrUnheldSupply) Error() string {
+	return fmt.Sprintf("no account holds %s of the token supply, the export has no staking state to carry "+
+		"the bonded tokens over: restart from an export holding them, or accept the loss explicitly", e.Unheld)
+}
+
+// Restart turns the genesis exported by okchaind into the genesis of a new
+// network: the accounts get back their exported units, the staking,
+// distribution and gov state is kept as exported with its heights reset to
+// zero, the sections the export leaves empty come from the template, and
+// the result runs through the module and launch validation.
+func Restart(exportFile string, cfg RestartConfig) (*tmtypes.GenesisDoc, RestartReport, error) {
+	var report RestartReport
+	if err := CheckChainID(cfg.ChainID); err != nil {
+		return nil, report, err
+	}
+	genesisDoc, genesisState, err := LoadGenesis(appCdc, exportFile)
+	if err != nil {
+		return nil, report, err
+	}
+	if genesisDoc.ChainID == cfg.ChainID {
+		return nil, report, fmt.Errorf("the new chain id must differ from the exported %s, or the old txs replay", cfg.ChainID)
+	}
+	_, template, err := LoadGenesis(appCdc, cfg.Template)
+	if err != nil {
+		return nil, report, err
+	}
+	report.ExportedChainID = genesisDoc.ChainID
+
+	// the app exports the coins counted in units as whole tokens
+	for i := range genesisState.Accounts {
+		genesisState.Accounts[i].Coins = fromExportedCoins(genesisState.Accounts[i].Coins)
+	}
+
+	if report.Filled, err = fillEmptySections(&genesisState, template); err != nil {
+		return nil, report, err
+	}
+	staking := &genesisState.StakingData
+	if containsString(report.Filled, "staking") {
+		// as for a new network, the whole bond denom supply starts in the not bonded pool
+		notBonded := sdk.ZeroInt()
+		for _, acc := range genesisState.Accounts {
+			notBonded = notBonded.Add(sdk.NewIntFromBigInt(acc.Coins.AmountOf(staking.Params.BondDenom).Int))
+		}
+		staking.Pool.NotBondedTokens = notBonded
+	}
+	report.DroppedSlashes = resetHeights(&genesisState)
+
+	// the validators carry over, or the gentxs of the new network create them
+	genTxs := NewBuilder(cfg.ChainID, cfg.GenesisTime)
+	if cfg.GenTxPath != "" {
+		if err := genTxs.AddGenTxs(cfg.GenTxPath); err != nil {
+			return nil, report, err
+		}
+	}
+	switch {
+	case len(staking.Validators) > 0 && len(genTxs.GenTxs()) > 0:
+		return nil, report, fmt.Errorf("%s has staking validators, the gentxs would create more", exportFile)
+	case len(staking.Validators) > 0:
+		staking.Exported = true
+		genesisState.GenTxs = nil
+	case len(genTxs.GenTxs()) > 0:
+		// the gentxs are signed with sequence 0, the chain id already keeps the old txs out
+		delegators := make(map[string]bool)
+		for _, genTx := range genTxs.GenTxs() {
+			if _, msg, err := DecodeGenTx(genTx.JSON); err == nil {
+				delegators[msg.DelegatorAddress.String()] = true
+			}
+		}
+		for i := range genesisState.Accounts {
+			if delegators[genesisState.Accounts[i].Address.String()] {
+				genesisState.Accounts[i].Sequence = 0
+			}
+		}
+		if err := genTxs.CheckGenTxs(genesisState.Accounts, staking.Params.BondDenom); err != nil {
+			return nil, report, err
+		}
+		genesisState.GenTxs = nil
+		for _, genTx := range genTxs.GenTxs() {
+			genesisState.GenTxs = append(genesisState.GenTxs, genTx.JSON)
+		}
+		// InitChain returns the validator set of the gentxs
+		genesisDoc.Validators = nil
+	default:
+		return nil, report, ErrNoValidators{exportFile}
+	}
+
+	// okchaind gives the tokens without an owner to the first account
+	genesisState.Sanitize()
+	for i := range genesisState.Token.Info {
+		t := &genesisState.Token.Info[i]
+		if t.Owner.Empty() && len(genesisState.Accounts) > 0 {
+			t.Owner = genesisState.Accounts[0].Address
+			report.ImplicitOwners = append(report.ImplicitOwners, t.Symbol)
+			report.FirstAccount = t.Owner
+		}
+	}
+	sortGenesisState(&genesisState)
+	if containsString(report.Filled, "staking") {
+		report.Unheld = unheldSupply(genesisState)
+		if !report.Unheld.IsZero() && !cfg.AllowUnheld {
+			return nil, report, ErrUnheldSupply{report.Unheld}
+		}
+	}
+	if err := ValidateGenesisState(genesisState); err != nil {
+		return nil, report, err
+	}
+
+	report.Accounts = len(genesisState.Accounts)
+	report.Validators = len(staking.Validators)
+	report.Delegations = len(staking.Delegations)
+	report.Unbonding = len(staking.UnbondingDelegations)
+	report.Redelegations = len(staking.Redelegations)
+	report.Proposals = len(genesisState.GovData.Proposals)
+	report.GenTxs = len(genesisState.GenTxs)
+
+	genesisDoc.ChainID = cfg.ChainID
+	genesisDoc.GenesisTime = cfg.GenesisTime
+	genesisDoc.AppHash = nil
+	if genesisDoc.AppState, err = appCdc.MarshalJSON(genesisState); err != nil {
+		return nil, report, err
+	}
+	if err := genesisDoc.ValidateAndComplete(); err != nil {
+		return nil, report, err
+	}
+	return genesisDoc, report, nil
+}
+
+// the supply of every token minus what the accounts hold
+func unheldSupply(genesisState app.GenesisState) sdk.DecCoins {
+	var unheld sdk.DecCoins
+	for _, t := range genesisState.Token.Info {
+		held := sdk.ZeroDec()
+		for _, acc := range genesisState.Accounts {
+			held = held.Add(acc.Coins.AmountOf(t.Symbol))
+		}
+		if supply := sdk.NewDec(t.TotalSupply); held.LT(supply) {
+			unheld = append(unheld, sdk.NewDecCoinFromDec(t.Symbol, supply.Sub(held)))
+		}
+	}
+	return unheld.Sort()
+}
+
+// fillEmptySections replaces the module sections exported with their zero
+// value, or the module defaults okchaind writes for auth, bank and gov, by
+// the template ones
+func fillEmptySections(genesisState *app.GenesisState, template app.GenesisState) ([]string, error) {
+	var filled []string
+	sections := []struct {
+		name               string
+		exported, fallback interface{}
+		defaults           interface{}
+	}{
+		{"auth", &genesisState.AuthData, &template.AuthData, auth.DefaultGenesisState()},
+		{"bank", &genesisState.BankData, &template.BankData, bank.DefaultGenesisState()},
+		{"staking", &genesisState.StakingData, &template.StakingData, nil},
+		{"distr", &genesisState.DistrData, &template.DistrData, nil},
+		{"slashing", &genesisState.SlashingData, &template.SlashingData, nil},
+		{"gov", &genesisState.GovData, &template.GovData, gov.DefaultGenesisState()},
+		{"mint", &genesisState.MintData, &template.MintData, nil},
+		{"order", &genesisState.Order, &template.Order, nil},
+		{"token", &genesisState.Token, &template.Token, nil},
+	}
+	for _, s := range sections {
+		exported := reflect.ValueOf(s.exported).Elem()
+		bz, err := appCdc.MarshalJSON(exported.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if s.defaults != nil {
+			defaults, err := decodedJSON(s.defaults, exported.Type())
+			if err != nil {
+				return nil, err
+			}
+			if bytes.Equal(bz, defaults) {
+				exported.Set(reflect.ValueOf(s.fallback).Elem())
+				filled = append(filled, s.name)
+				continue
+			}
+		}
+		zero, err := decodedJSON(reflect.Zero(exported.Type()).Interface(), exported.Type())
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(bz, zero) {
+			exported.Set(reflect.ValueOf(s.fallback).Elem())
+			filled = append(filled, s.name)
+		}
+	}
+	return filled, nil
+}
+
+// decodedJSON is the json of a value once decoded from its own json, as the
+// exported sections are: nil decimals come back as zeros, empty lists as nil
+func decodedJSON(value interface{}, t reflect.Type) ([]byte, error) {
+	bz, err := appCdc.MarshalJSON(value)
+	if err != nil {
+		return nil, err
+	}
+	decoded := reflect.New(t)
+	if err := appCdc.UnmarshalJSON(bz, decoded.Interface()); err != nil {
+		return nil, err
+	}
+	return appCdc.MarshalJSON(decoded.Elem().Interface())
+}
+
+// resetHeights moves the height dependent state to height zero, the one of
+// the new genesis, and returns the number of slash events dropped: their
+// heights mean nothing on the new chain
+func resetHeights(genesisState *app.GenesisState) int {
+	staking := &genesisState.StakingData
+	for i := range staking.Validators {
+		staking.Validators[i].UnbondingHeight = 0
+	}
+	for i := range staking.UnbondingDelegations {
+		for j := range staking.UnbondingDelegations[i].Entries {
+			staking.UnbondingDelegations[i].Entries[j].CreationHeight = 0
+		}
+	}
+	for i := range staking.Redelegations {
+		for j := range staking.Redelegations[i].Entries {
+			staking.Redelegations[i].Entries[j].CreationHeight = 0
+		}
+	}
+
+	distr := &genesisState.DistrData
+	for i := range distr.DelegatorStartingInfos {
+		distr.DelegatorStartingInfos[i].StartingInfo.Height = 0
+	}
+	dropped := len(distr.ValidatorSlashEvents)
+	distr.ValidatorSlashEvents = nil
+
+	signingInfos := make(map[string]slashing.ValidatorSigningInfo, len(genesisState.SlashingData.SigningInfos))
+	for addr, info := range genesisState.SlashingData.SigningInfos {
+		info.StartHeight, info.IndexOffset, info.MissedBlocksCounter = 0, 0, 0
+		signingInfos[addr] = info
+	}
+	genesisState.SlashingData.SigningInfos = signingInfos
+	genesisState.SlashingData.MissedBlocks = make(map[string][]slashing.MissedBlock)
+	return dropped
+}
+
+// WriteRestartReport prints what a restart kept and filled in
+func WriteRestartReport(w io.Writer, report RestartReport) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "exported chain\t%s\n", report.ExportedChainID)
+	fmt.Fprintf(tw, "accounts\t%d\n", report.Accounts)
+	fmt.Fprintf(tw, "validators\t%d\n", report.Validators)
+	fmt.Fprintf(tw, "delegations\t%d\n", report.Delegations)
+	fmt.Fprintf(tw, "unbonding delegations\t%d\n", report.Unbonding)
+	fmt.Fprintf(tw, "redelegations\t%d\n", report.Redelegations)
+	fmt.Fprintf(tw, "gov proposals\t%d\n", report.Proposals)
+	fmt.Fprintf(tw, "gentxs\t%d\n", report.GenTxs)
+	if len(report.Filled) > 0 {
+		fmt.Fprintf(tw, "from the template\t%s\n", strings.Join(report.Filled, ", "))
+	}
+	if !report.Unheld.IsZero() {
+		fmt.Fprintf(tw, "supply no account holds\t%s\n", report.Unheld)
+	}
+	if report.DroppedSlashes > 0 {
+		fmt.Fprintf(tw, "slash events dropped\t%d\n", report.DroppedSlashes)
+	}
+	if len(report.ImplicitOwners) > 0 {
+		fmt.Fprintf(tw, "owned by the first account\t%s (%s)\n", strings.Join(report.ImplicitOwners, ", "), report.FirstAccount)
+	}
+	return tw.Flush()
+}
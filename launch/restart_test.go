@@ -0,0 +1,234 @@
+package launch
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/slashing"
+	"github.com/ok-chain/okchain/app"
+	distr "github.com/ok-chain/okchain/x/distribution/types"
+	"github.com/ok-chain/okchain/x/staking"
+	stakingtypes "github.com/ok-chain/okchain/x/staking/types"
+	"github.com/ok-chain/okchain/x/token"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+var restartTime = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func writeTestGenesis(t *testing.T, name, chainID string, state app.GenesisState) string {
+	t.Helper()
+	appState, err := appCdc.MarshalJSON(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := writeTestFile(t, name, "")
+	doc := &tmtypes.GenesisDoc{ChainID: chainID, GenesisTime: restartTime, AppState: appState}
+	if err := doc.SaveAs(file); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func restartTemplate() app.GenesisState {
+	template := app.NewDefaultGenesisState()
+	template.StakingData.Params.MaxValidators = 7
+	return template
+}
+
+// an okchaind export: accounts holding units as whole tokens, nothing else
+// but the tokens
+func accountsExport(supply int64) app.GenesisState {
+	var state app.GenesisState
+	for _, name := range []string{"alice", "bob"} {
+		state.Accounts = append(state.Accounts, app.GenesisAccount{
+			Address: testOperator(name), Coins: newCoins("okb", sdk.NewDec(1e8)), Sequence: 5,
+		})
+	}
+	state.Token.Info = []token.Token{{Name: "okb", Symbol: "okb", OriginalSymbol: "okb", TotalSupply: supply}}
+	return state
+}
+
+func TestFillEmptySections(t *testing.T) {
+	// decoded as LoadGenesis does, the empty sections hold zero decimals
+	var exported app.GenesisState
+	bz, err := appCdc.MarshalJSON(app.GenesisState{
+		AuthData: auth.DefaultGenesisState(),
+		Token:    token.GenesisState{Info: []token.Token{{Symbol: "okb"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := appCdc.UnmarshalJSON(bz, &exported); err != nil {
+		t.Fatal(err)
+	}
+	filled, err := fillEmptySections(&exported, restartTemplate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"auth", "bank", "staking", "distr", "slashing", "gov", "mint", "order"}
+	if !reflect.DeepEqual(filled, want) {
+		t.Errorf("expected %v filled, got %v", want, filled)
+	}
+	if exported.StakingData.Params.MaxValidators != 7 || len(exported.Token.Info) != 1 {
+		t.Errorf("expected the template staking and the exported tokens, got %+v and %+v", exported.StakingData.Params, exported.Token)
+	}
+
+	// exported sections are kept
+	exported = restartTemplate()
+	exported.StakingData.Params.MaxValidators = 3
+	exported.AuthData.Params.MaxMemoCharacters++
+	if filled, err = fillEmptySections(&exported, restartTemplate()); err != nil {
+		t.Fatal(err)
+	}
+	if containsString(filled, "staking") || containsString(filled, "auth") || exported.StakingData.Params.MaxValidators != 3 {
+		t.Errorf("expected the exported staking and auth to be kept, got %v filled", filled)
+	}
+}
+
+func TestResetHeights(t *testing.T) {
+	state := app.NewDefaultGenesisState()
+	val := staking.NewValidator(sdk.ValAddress(testOperator("alice")), ed25519.GenPrivKeyFromSecret([]byte("alice")).PubKey(),
+		staking.NewDescription("alice", "", "", ""))
+	val.UnbondingHeight = 10
+	state.StakingData.Validators = staking.Validators{val}
+	state.StakingData.UnbondingDelegations = []staking.UnbondingDelegation{{Entries: []stakingtypes.UnbondingDelegationEntry{{CreationHeight: 11}}}}
+	state.StakingData.Redelegations = []staking.Redelegation{{Entries: []stakingtypes.RedelegationEntry{{CreationHeight: 12}}}}
+	state.DistrData.DelegatorStartingInfos = []distr.DelegatorStartingInfoRecord{{StartingInfo: distr.DelegatorStartingInfo{Height: 13}}}
+	state.DistrData.ValidatorSlashEvents = []distr.ValidatorSlashEventRecord{{Height: 14}, {Height: 15}}
+	state.SlashingData.SigningInfos = map[string]slashing.ValidatorSigningInfo{
+		"alice": {StartHeight: 16, IndexOffset: 17, MissedBlocksCounter: 18, Tombstoned: true},
+	}
+	state.SlashingData.MissedBlocks = map[string][]slashing.MissedBlock{"alice": {{Index: 1, Missed: true}}}
+
+	if dropped := resetHeights(&state); dropped != 2 {
+		t.Errorf("expected 2 slash events dropped, got %d", dropped)
+	}
+	stakingData := state.StakingData
+	if stakingData.Validators[0].UnbondingHeight != 0 || stakingData.UnbondingDelegations[0].Entries[0].CreationHeight != 0 ||
+		stakingData.Redelegations[0].Entries[0].CreationHeight != 0 {
+		t.Errorf("expected the staking heights reset, got %+v", stakingData)
+	}
+	if state.DistrData.DelegatorStartingInfos[0].StartingInfo.Height != 0 || len(state.DistrData.ValidatorSlashEvents) != 0 {
+		t.Errorf("expected the distribution heights reset, got %+v", state.DistrData)
+	}
+	info := state.SlashingData.SigningInfos["alice"]
+	if info.StartHeight != 0 || info.IndexOffset != 0 || info.MissedBlocksCounter != 0 || !info.Tombstoned {
+		t.Errorf("expected the signing info heights reset, got %+v", info)
+	}
+	if len(state.SlashingData.MissedBlocks) != 0 {
+		t.Errorf("expected no missed blocks, got %v", state.SlashingData.MissedBlocks)
+	}
+}
+
+func TestRestartGenTxs(t *testing.T) {
+	genTx := newGenTxFixture("alice").sign(t)
+	genTxPath := filepath.Dir(writeTestFile(t, filepath.Base(genTx.File), string(genTx.JSON)))
+	template := writeTestGenesis(t, "template.json", "template", restartTemplate())
+
+	cases := []struct {
+		name        string
+		supply      int64
+		allowUnheld bool
+		unheld      string // empty when the restart must fail
+	}{
+		{"held", 2, false, ""},
+		{"unheld", 3, false, "fail"},
+		{"unheld allowed", 3, true, "1.00000000okb"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			export := writeTestGenesis(t, "export.json", "old-chain", accountsExport(tc.supply))
+			cfg := RestartConfig{ChainID: testChainID, GenesisTime: restartTime, Template: template,
+				GenTxPath: genTxPath, AllowUnheld: tc.allowUnheld}
+			doc, report, err := Restart(export, cfg)
+			if tc.unheld == "fail" {
+				if _, ok := err.(ErrUnheldSupply); !ok {
+					t.Fatalf("expected ErrUnheldSupply, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Unheld.String() != tc.unheld {
+				t.Errorf("expected %q unheld, got %s", tc.unheld, report.Unheld)
+			}
+			if report.GenTxs != 1 || report.Validators != 0 || report.ExportedChainID != "old-chain" ||
+				!containsString(report.Filled, "staking") {
+				t.Errorf("unexpected report %+v", report)
+			}
+
+			var state app.GenesisState
+			if err := appCdc.UnmarshalJSON(doc.AppState, &state); err != nil {
+				t.Fatal(err)
+			}
+			if doc.ChainID != testChainID || !doc.GenesisTime.Equal(restartTime) || len(doc.Validators) != 0 {
+				t.Errorf("unexpected genesis doc %s at %v with %d validators", doc.ChainID, doc.GenesisTime, len(doc.Validators))
+			}
+			// the delegator signed its gentx with sequence 0, the other account keeps its own
+			for _, acc := range state.Accounts {
+				want := uint64(5)
+				if acc.Address.Equals(testOperator("alice")) {
+					want = 0
+				}
+				if acc.Sequence != want || !acc.Coins.AmountOf("okb").Equal(sdk.NewDec(1)) {
+					t.Errorf("unexpected account %s: sequence %d, coins %s", acc.Address, acc.Sequence, acc.Coins)
+				}
+			}
+			if want := sdk.NewInt(2e8); !state.StakingData.Pool.NotBondedTokens.Equal(want) {
+				t.Errorf("expected %v not bonded, got %v", want, state.StakingData.Pool.NotBondedTokens)
+			}
+		})
+	}
+}
+
+func TestRestartExportedValidators(t *testing.T) {
+	template := writeTestGenesis(t, "template.json", "template", restartTemplate())
+
+	exported := restartTemplate()
+	exported.Accounts = accountsExport(2).Accounts
+	exported.Token.Info = accountsExport(2).Token.Info
+	val := staking.NewValidator(sdk.ValAddress(testOperator("alice")), ed25519.GenPrivKeyFromSecret([]byte("alice")).PubKey(),
+		staking.NewDescription("alice", "", "", ""))
+	val.Status, val.Tokens, val.DelegatorShares, val.UnbondingHeight = sdk.Bonded, sdk.NewInt(1e8), sdk.NewDec(1), 10
+	exported.StakingData.Validators = staking.Validators{val}
+	export := writeTestGenesis(t, "export.json", "old-chain", exported)
+
+	cfg := RestartConfig{ChainID: testChainID, GenesisTime: restartTime, Template: template}
+	doc, report, err := Restart(export, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Validators != 1 || report.GenTxs != 0 || containsString(report.Filled, "staking") {
+		t.Errorf("unexpected report %+v", report)
+	}
+	var state app.GenesisState
+	if err := appCdc.UnmarshalJSON(doc.AppState, &state); err != nil {
+		t.Fatal(err)
+	}
+	if !state.StakingData.Exported || state.StakingData.Validators[0].UnbondingHeight != 0 || len(state.GenTxs) != 0 {
+		t.Errorf("expected the exported validators at height zero, got %+v", state.StakingData)
+	}
+
+	// the gentxs can't add validators to the exported ones
+	genTx := newGenTxFixture("alice").sign(t)
+	cfg.GenTxPath = filepath.Dir(writeTestFile(t, filepath.Base(genTx.File), string(genTx.JSON)))
+	if _, _, err := Restart(export, cfg); err == nil || !strings.Contains(err.Error(), "gentxs would create more") {
+		t.Errorf("expected the gentxs to be refused, got %v", err)
+	}
+
+	// nor can the validators be missing from both
+	cfg.GenTxPath = ""
+	noValidators := writeTestGenesis(t, "export.json", "old-chain", accountsExport(2))
+	if _, _, err := Restart(noValidators, cfg); err == nil {
+		t.Error("expected a restart without validators to fail")
+	} else if _, ok := err.(ErrNoValidators); !ok {
+		t.Errorf("expected ErrNoValidators, got %v", err)
+	}
+}
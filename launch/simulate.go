@@ -0,0 +1,361 @@
+package launch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/common"
+	"github.com/ok-chain/okchain/x/staking"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+	"github.com/tendermint/tendermint/libs/log"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// time between two simulated blocks
+const simulatedBlockTime = 5 * time.Second
+
+// the innermost module frame of a stack trace, vendored or not
+var reModuleFrame = regexp.MustCompile(`github\.com/(?:ok-chain/okchain|cosmos/cosmos-sdk)/x/([a-z_]+)\.`)
+
+// SimulatedValidator is a validator of the simulated chain
+type SimulatedValidator struct {
+	Address   string // consensus address
+	Moniker   string // from the gentx, empty when unknown
+	Validator string // operator address from the gentx
+	Power     int64  // power given to the app by the gentx
+	Simulated int64  // power returned by the app
+}
+
+// Simulation is the state of the chain after the simulated blocks
+type Simulation struct {
+	Height     int64
+	AppHash    []byte
+	Validators []SimulatedValidator
+	Genesis    []app.GenesisAccount
+	Accounts   []app.GenesisAccount
+}
+
+// ErrSimulationPanic is returned when the app panics during the simulation
+type ErrSimulationPanic struct {
+	Stage  string
+	Module string
+	Value  interface{}
+	Stack  []byte
+}
+
+func (e ErrSimulationPanic) Error() string {
+	module := e.Module
+	if module == "" {
+		module = "unknown module"
+	}
+	return fmt.Sprintf("app panicked during %s in %s: %v", e.Stage, module, e.Value)
+}
+
+// Simulate boots the genesis doc on an in-memory DexApp: InitChain with the
+// validator set of the gentxs, then the given number of empty blocks.
+func Simulate(genesisDoc *tmtypes.GenesisDoc, blocks int) (*Simulation, error) {
+	var genesisState app.GenesisState
+	if err := appCdc.UnmarshalJSON(genesisDoc.AppState, &genesisState); err != nil {
+		return nil, fmt.Errorf("decoding app state: %v", err)
+	}
+	if err := genesisDoc.ValidateAndComplete(); err != nil {
+		return nil, err
+	}
+
+	sim := &Simulation{Genesis: genesisState.Accounts}
+	validators, err := genTxValidators(genesisState)
+	if err != nil {
+		return nil, err
+	}
+
+	// the app dumps a default maintain.conf in its config directory,
+	// keep it away from the node home
+	configDir, err := ioutil.TempDir("", "launch-simulate")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(configDir)
+	nodeConfig := app.DefaultNodeCofig
+	app.DefaultNodeCofig = configDir
+	defer func() { app.DefaultNodeCofig = nodeConfig }()
+
+	var dexApp *app.DexApp
+	err = catchPanic("app setup", func() {
+		dexApp = app.DecentralizedExchangeApp(log.NewNopLogger(), db.NewMemDB())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req := abci.RequestInitChain{
+		Time:            genesisDoc.GenesisTime,
+		ChainId:         genesisDoc.ChainID,
+		ConsensusParams: tmtypes.TM2PB.ConsensusParams(genesisDoc.ConsensusParams),
+		Validators:      validators,
+		AppStateBytes:   genesisDoc.AppState,
+	}
+
+	var res abci.ResponseInitChain
+	if err := catchPanic("InitChain", func() { res = dexApp.InitChain(req) }); err != nil {
+		return nil, err
+	}
+	powers := make(map[string]int64)
+	applyValidatorUpdates(powers, res.Validators)
+
+	for h := int64(1); h <= int64(blocks); h++ {
+		if len(powers) == 0 {
+			return nil, fmt.Errorf("no validator left at height %d, the chain can't produce blocks", h)
+		}
+		// every validator signs and they take turns to propose
+		votes := lastCommitVotes(powers)
+		header := abci.Header{
+			ChainID:         genesisDoc.ChainID,
+			Height:          h,
+			Time:            genesisDoc.GenesisTime.Add(time.Duration(h) * simulatedBlockTime),
+			ProposerAddress: votes[int(h)%len(votes)].Validator.Address,
+		}
+		stage := fmt.Sprintf("block %d", h)
+		err := catchPanic(stage, func() {
+			dexApp.BeginBlock(abci.RequestBeginBlock{
+				Header:         header,
+				LastCommitInfo: abci.LastCommitInfo{Votes: votes},
+			})
+			end := dexApp.EndBlock(abci.RequestEndBlock{Height: h})
+			applyValidatorUpdates(powers, end.ValidatorUpdates)
+			sim.AppHash = dexApp.Commit().Data
+		})
+		if err != nil {
+			return nil, err
+		}
+		sim.Height = h
+	}
+
+	var exportErr error
+	err = catchPanic("export", func() {
+		var appState []byte
+		appState, _, exportErr = dexApp.ExportAppStateAndValidators()
+		if exportErr != nil {
+			return
+		}
+		var exported app.GenesisState
+		if exportErr = appCdc.UnmarshalJSON(appState, &exported); exportErr != nil {
+			return
+		}
+		for _, acc := range exported.Accounts {
+			acc.Coins = fromExportedCoins(acc.Coins)
+			sim.Accounts = append(sim.Accounts, acc)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if exportErr != nil {
+		return nil, fmt.Errorf("exporting the state: %v", exportErr)
+	}
+
+	sim.Validators = simulatedValidators(genesisState, powers)
+	return sim, nil
+}
+
+// genTxValidators returns the validator set the gentxs should produce
+func genTxValidators(genesisState app.GenesisState) ([]abci.ValidatorUpdate, error) {
+	var validators []abci.ValidatorUpdate
+	for i, genTx := range genesisState.GenTxs {
+		_, msg, err := DecodeGenTx(genTx)
+		if err != nil {
+			return nil, fmt.Errorf("gentx %d: %v", i, err)
+		}
+		validators = append(validators, abci.ValidatorUpdate{
+			PubKey: tmtypes.TM2PB.PubKey(msg.PubKey),
+			Power:  tendermintPower(msg.Value.Amount),
+		})
+	}
+	for _, val := range exportedValidators(genesisState) {
+		validators = append(validators, abci.ValidatorUpdate{
+			PubKey: tmtypes.TM2PB.PubKey(val.ConsPubKey),
+			Power:  tendermintPower(val.Tokens),
+		})
+	}
+	return validators, nil
+}
+
+// a restarted chain carries its bonded validators over in the staking state
+func exportedValidators(genesisState app.GenesisState) []staking.Validator {
+	if !genesisState.StakingData.Exported {
+		return nil
+	}
+	var validators []staking.Validator
+	for _, val := range genesisState.StakingData.Validators {
+		if val.Status == sdk.Bonded && !val.Jailed {
+			validators = append(validators, val)
+		}
+	}
+	return validators
+}
+
+// the staking module of the fork gives one power per whole bonded token
+func tendermintPower(units sdk.Int) int64 {
+	return units.Quo(sdk.NewIntWithDecimal(1, sdk.Precision)).Int64()
+}
+
+// the app exports the coins counted in units as whole tokens, scale them back
+func fromExportedCoins(coins sdk.DecCoins) sdk.DecCoins {
+	units := make(sdk.Coins, 0, len(coins))
+	for _, coin := range coins {
+		units = append(units, sdk.NewCoin(coin.Denom, coin.Amount.TruncateInt()))
+	}
+	return common.ConvertCoinsToDecCoins(units)
+}
+
+func applyValidatorUpdates(powers map[string]int64, updates []abci.ValidatorUpdate) {
+	for _, u := range updates {
+		pk, err := tmtypes.PB2TM.PubKey(u.PubKey)
+		if err != nil {
+			// the app only hands out pubkeys it decoded itself
+			panic(err)
+		}
+		addr := sdk.ConsAddress(pk.Address()).String()
+		if u.Power == 0 {
+			delete(powers, addr)
+		} else {
+			powers[addr] = u.Power
+		}
+	}
+}
+
+// lastCommitVotes returns the votes of every validator, by address
+func lastCommitVotes(powers map[string]int64) []abci.VoteInfo {
+	votes := make([]abci.VoteInfo, 0, len(powers))
+	for addr, power := range powers {
+		consAddr, err := sdk.ConsAddressFromBech32(addr)
+		if err != nil {
+			// encoded by applyValidatorUpdates
+			panic(err)
+		}
+		votes = append(votes, abci.VoteInfo{
+			Validator:       abci.Validator{Address: consAddr, Power: power},
+			SignedLastBlock: true,
+		})
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		return bytes.Compare(votes[i].Validator.Address, votes[j].Validator.Address) < 0
+	})
+	return votes
+}
+
+// simulatedValidators merges the gentx validators with the simulated powers
+func simulatedValidators(genesisState app.GenesisState, powers map[string]int64) []SimulatedValidator {
+	byAddr := make(map[string]*SimulatedValidator)
+	for _, genTx := range genesisState.GenTxs {
+		_, msg, err := DecodeGenTx(genTx)
+		if err != nil {
+			// already decoded by genTxValidators
+			continue
+		}
+		addr := sdk.ConsAddress(msg.PubKey.Address()).String()
+		byAddr[addr] = &SimulatedValidator{
+			Address:   addr,
+			Moniker:   msg.Description.Moniker,
+			Validator: msg.ValidatorAddress.String(),
+			Power:     tendermintPower(msg.Value.Amount),
+		}
+	}
+	for _, val := range exportedValidators(genesisState) {
+		addr := sdk.ConsAddress(val.ConsPubKey.Address()).String()
+		byAddr[addr] = &SimulatedValidator{
+			Address:   addr,
+			Moniker:   val.Description.Moniker,
+			Validator: val.OperatorAddress.String(),
+			Power:     tendermintPower(val.Tokens),
+		}
+	}
+	for addr, power := range powers {
+		if _, ok := byAddr[addr]; !ok {
+			byAddr[addr] = &SimulatedValidator{Address: addr}
+		}
+		byAddr[addr].Simulated = power
+	}
+
+	validators := make([]SimulatedValidator, 0, len(byAddr))
+	for _, v := range byAddr {
+		validators = append(validators, *v)
+	}
+	sort.Slice(validators, func(i, j int) bool {
+		if validators[i].Simulated != validators[j].Simulated {
+			return validators[i].Simulated > validators[j].Simulated
+		}
+		return validators[i].Address < validators[j].Address
+	})
+	return validators
+}
+
+// CheckPowers checks every gentx validator got the power of its self
+// delegation, and every exported one the power of its tokens
+func (sim *Simulation) CheckPowers() error {
+	for _, v := range sim.Validators {
+		if v.Power != v.Simulated {
+			return fmt.Errorf("validator %s (%s) has power %d, expected %d at genesis",
+				v.Address, v.Moniker, v.Simulated, v.Power)
+		}
+	}
+	return nil
+}
+
+// catchPanic runs f and turns a panic into an ErrSimulationPanic
+func catchPanic(stage string, f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			stack := debug.Stack()
+			e := ErrSimulationPanic{Stage: stage, Value: r, Stack: stack}
+			if m := reModuleFrame.FindSubmatch(stack); m != nil {
+				e.Module = string(m[1])
+			}
+			err = e
+		}
+	}()
+	f()
+	return nil
+}
+
+// WriteSimulation prints the simulated validators and balances as tables
+func WriteSimulation(w io.Writer, sim *Simulation) error {
+	fmt.Fprintf(w, "height %d, app hash %X\n\n", sim.Height, sim.AppHash)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VALIDATOR\tMONIKER\tOPERATOR\tGENTX POWER\tPOWER")
+	for _, v := range sim.Validators {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\n", v.Address, v.Moniker, v.Validator, v.Power, v.Simulated)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+
+	genesis := make(map[string]sdk.DecCoins, len(sim.Genesis))
+	for _, acc := range sim.Genesis {
+		genesis[acc.Address.String()] = acc.Coins
+	}
+	accounts := append([]app.GenesisAccount(nil), sim.Accounts...)
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].Address.String() < accounts[j].Address.String()
+	})
+
+	tw = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACCOUNT\tGENESIS\tBALANCE")
+	for _, acc := range accounts {
+		addr := acc.Address.String()
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", addr, genesis[addr], acc.Coins)
+	}
+	return tw.Flush()
+}
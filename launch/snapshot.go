@@ -0,0 +1,459 @@
+package launch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/x/token"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/bech32"
+)
+
+// the module accounts of the cosmos-sdk modules, their balances are the
+// pools and escrows of the chain (bonded stake, rewards, deposits) and
+// belong to the holders counted otherwise
+var snapshotModules = []string{
+	"bonded_tokens_pool", "not_bonded_tokens_pool", "distribution", "gov", "mint", "fee_collector",
+}
+
+// SnapshotConfig turns the balances of an exported chain into allocations
+type SnapshotConfig struct {
+	Decimals  int               // of the exported amounts, which are counted in units
+	Ratio     sdk.Dec           // new tokens per exported token
+	Threshold sdk.Dec           // smaller allocations are dropped as dust
+	Denoms    map[string]string // exported denom -> allocated denom, every denom as is when empty
+}
+
+// SnapshotHolder is the balance of an address in one allocated denom,
+// in exported tokens and, after the ratio, in allocated ones
+type SnapshotHolder struct {
+	Address   sdk.AccAddress
+	Denom     string
+	Liquid    sdk.Dec
+	Bonded    sdk.Dec
+	Unbonding sdk.Dec
+	Amount    sdk.Dec
+}
+
+// Snapshot is the airdrop taken from an exported genesis
+type Snapshot struct {
+	ChainID  string
+	Staking  bool // whether the export holds a staking state
+	Holders  []SnapshotHolder
+	Dust     []SnapshotHolder
+	Unmapped map[string]int // exported denoms left out, with their holder count
+	Modules  []string       // module accounts left out, by name or address
+}
+
+// the parts of an exported genesis a snapshot reads, as plain json so that
+// any cosmos-sdk export can be read, whatever its codec registrations
+type exportedGenesis struct {
+	ChainID  string `json:"chain_id"`
+	AppState struct {
+		Accounts []json.RawMessage `json:"accounts"`
+		Auth     struct {
+			Accounts []json.RawMessage `json:"accounts"`
+		} `json:"auth"`
+		Staking struct {
+			Params struct {
+				BondDenom string `json:"bond_denom"`
+			} `json:"params"`
+			Validators []struct {
+				OperatorAddress string  `json:"operator_address"`
+				Tokens          big.Rat `json:"tokens"`
+				DelegatorShares big.Rat `json:"delegator_shares"`
+			} `json:"validators"`
+			Delegations []struct {
+				DelegatorAddress string  `json:"delegator_address"`
+				ValidatorAddress string  `json:"validator_address"`
+				Shares           big.Rat `json:"shares"`
+			} `json:"delegations"`
+			UnbondingDelegations []struct {
+				DelegatorAddress string `json:"delegator_address"`
+				Entries          []struct {
+					Balance big.Rat `json:"balance"`
+				} `json:"entries"`
+			} `json:"unbonding_delegations"`
+		} `json:"staking"`
+	} `json:"app_state"`
+}
+
+type exportedAccount struct {
+	Address string `json:"address"`
+	Coins   []struct {
+		Denom  string  `json:"denom"`
+		Amount big.Rat `json:"amount"`
+	} `json:"coins"`
+	ModuleName string `json:"module_name"` // genesis accounts of a module
+	Module     string `json:"-"`           // module name when the account is a module account
+}
+
+// the balances of an address in units, per exported denom
+type exportedBalance struct {
+	liquid, bonded, unbonding map[string]*big.Rat
+}
+
+// TakeSnapshot reads the liquid, bonded and unbonding balances of every
+// holder of an exported genesis, maps their denoms and applies the ratio.
+// The accounts are read from app_state.accounts, as okchaind exports them,
+// or from a cosmos-sdk app_state.auth.accounts list. Module accounts are left
+// out, their balances are counted as the bonded and unbonding balances of
+// the delegators. Every delegation must be to an exported validator.
+// Amounts are counted in units: okchaind exports the units of the coins as
+// whole tokens, the integer part of an amount is the number of units either
+// way.
+func TakeSnapshot(file string, cfg SnapshotConfig) (*Snapshot, error) {
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+	var export exportedGenesis
+	if err := json.Unmarshal(bz, &export); err != nil {
+		return nil, ErrBadFile{file, err}
+	}
+	for from, to := range cfg.Denoms {
+		if !token.ValidCoinName(to) {
+			return nil, fmt.Errorf("bad denom %q for %s: expected [a-z][a-z0-9]{2,15}", to, from)
+		}
+	}
+
+	balances := make(map[string]*exportedBalance)
+	balance := func(address string) (*exportedBalance, error) {
+		addr, err := anyBech32(address)
+		if err != nil {
+			return nil, ErrBadFile{file, fmt.Errorf("bad address %q: %v", address, err)}
+		}
+		b, ok := balances[addr.String()]
+		if !ok {
+			b = &exportedBalance{make(map[string]*big.Rat), make(map[string]*big.Rat), make(map[string]*big.Rat)}
+			balances[addr.String()] = b
+		}
+		return b, nil
+	}
+
+	accounts := export.AppState.Accounts
+	if len(accounts) == 0 {
+		accounts = export.AppState.Auth.Accounts
+	}
+	if len(accounts) == 0 {
+		return nil, ErrBadFile{file, fmt.Errorf("no account in app_state.accounts or app_state.auth.accounts")}
+	}
+	modules := make(map[string]string, len(snapshotModules))
+	for _, name := range snapshotModules {
+		modules[sdk.AccAddress(tmhash.SumTruncated([]byte(name))).String()] = name
+	}
+	var skipped []string
+	for i, raw := range accounts {
+		acc, err := decodeExportedAccount(raw)
+		if err != nil {
+			return nil, ErrBadFile{file, fmt.Errorf("account %d: %v", i, err)}
+		}
+		if acc.Module == "" {
+			if addr, err := anyBech32(acc.Address); err == nil {
+				acc.Module = modules[addr.String()]
+			}
+		}
+		if acc.Module != "" {
+			skipped = append(skipped, acc.Module)
+			continue
+		}
+		b, err := balance(acc.Address)
+		if err != nil {
+			return nil, err
+		}
+		for _, coin := range acc.Coins {
+			addUnits(b.liquid, coin.Denom, truncateRat(&coin.Amount))
+		}
+	}
+
+	staking := export.AppState.Staking
+	bondDenom := staking.Params.BondDenom
+	if bondDenom == "" {
+		bondDenom = okbDenomination
+	}
+	// a delegation is worth its share of the tokens of its validator
+	tokensPerShare := make(map[string]*big.Rat, len(staking.Validators))
+	for _, val := range staking.Validators {
+		rate := new(big.Rat)
+		if val.DelegatorShares.Sign() > 0 {
+			rate.Quo(&val.Tokens, &val.DelegatorShares)
+		}
+		tokensPerShare[val.OperatorAddress] = rate
+	}
+	for _, del := range staking.Delegations {
+		rate, ok := tokensPerShare[del.ValidatorAddress]
+		if !ok {
+			return nil, ErrBadFile{file, fmt.Errorf("delegation of %s to %s: the validator isn't exported",
+				del.DelegatorAddress, del.ValidatorAddress)}
+		}
+		b, err := balance(del.DelegatorAddress)
+		if err != nil {
+			return nil, err
+		}
+		addUnits(b.bonded, bondDenom, truncateRat(new(big.Rat).Mul(&del.Shares, rate)))
+	}
+	for _, ubd := range staking.UnbondingDelegations {
+		b, err := balance(ubd.DelegatorAddress)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range ubd.Entries {
+			addUnits(b.unbonding, bondDenom, truncateRat(&entry.Balance))
+		}
+	}
+
+	snapshot := &Snapshot{
+		ChainID:  export.ChainID,
+		Staking:  len(staking.Validators) > 0,
+		Unmapped: make(map[string]int),
+		Modules:  skipped,
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(cfg.Decimals)), nil))
+	ratio := decToRat(cfg.Ratio)
+	for addr, b := range balances {
+		accAddr, _ := sdk.AccAddressFromBech32(addr)
+		holders := make(map[string]*SnapshotHolder)
+		unmapped := make(map[string]bool)
+		for i, part := range []map[string]*big.Rat{b.liquid, b.bonded, b.unbonding} {
+			for denom, units := range part {
+				to := denom
+				if len(cfg.Denoms) > 0 {
+					if to = cfg.Denoms[denom]; to == "" {
+						unmapped[denom] = true
+						continue
+					}
+				}
+				h, ok := holders[to]
+				if !ok {
+					h = &SnapshotHolder{Address: accAddr, Denom: to, Liquid: sdk.ZeroDec(), Bonded: sdk.ZeroDec(), Unbonding: sdk.ZeroDec()}
+					holders[to] = h
+				}
+				tokens := floorToDec(new(big.Rat).Quo(units, scale))
+				switch i {
+				case 0:
+					h.Liquid = h.Liquid.Add(tokens)
+				case 1:
+					h.Bonded = h.Bonded.Add(tokens)
+				case 2:
+					h.Unbonding = h.Unbonding.Add(tokens)
+				}
+			}
+		}
+		for denom := range unmapped {
+			snapshot.Unmapped[denom]++
+		}
+		for _, h := range holders {
+			total := decToRat(h.Liquid.Add(h.Bonded).Add(h.Unbonding))
+			h.Amount = floorToDec(total.Mul(total, ratio))
+			if !h.Amount.IsPositive() || h.Amount.LT(cfg.Threshold) {
+				snapshot.Dust = append(snapshot.Dust, *h)
+			} else {
+				snapshot.Holders = append(snapshot.Holders, *h)
+			}
+		}
+	}
+	sortHolders(snapshot.Holders)
+	sortHolders(snapshot.Dust)
+	return snapshot, nil
+}
+
+// auth accounts are amino wrapped, {"type": ..., "value": {...}}, and the
+// vesting ones nest their base account
+func decodeExportedAccount(raw json.RawMessage) (exportedAccount, error) {
+	var acc exportedAccount
+	module := ""
+	for depth := 0; depth < 4; depth++ {
+		if err := json.Unmarshal(raw, &acc); err != nil {
+			return acc, err
+		}
+		if module == "" {
+			module = acc.ModuleName
+		}
+		if acc.Address != "" {
+			acc.Module = module
+			return acc, nil
+		}
+		var wrapper map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &wrapper); err != nil {
+			return acc, err
+		}
+		// a cosmos-sdk module account, {"type": ".../ModuleAccount", "value": {"name": ...}}
+		var typ string
+		if json.Unmarshal(wrapper["type"], &typ) == nil && strings.HasSuffix(typ, "/ModuleAccount") {
+			var value struct {
+				Name string `json:"name"`
+			}
+			json.Unmarshal(wrapper["value"], &value)
+			module = value.Name
+			if module == "" {
+				module = typ
+			}
+		}
+		next, ok := json.RawMessage(nil), false
+		for _, key := range []string{"value", "base_vesting_account", "BaseVestingAccount", "base_account", "BaseAccount"} {
+			if next, ok = wrapper[key]; ok {
+				break
+			}
+		}
+		if !ok {
+			break
+		}
+		raw = next
+	}
+	return acc, fmt.Errorf("no address")
+}
+
+// anyBech32 decodes an account address whatever its bech32 prefix
+func anyBech32(address string) (sdk.AccAddress, error) {
+	_, bz, err := bech32.DecodeAndConvert(address)
+	if err != nil {
+		return nil, err
+	}
+	if len(bz) != sdk.AddrLen {
+		return nil, fmt.Errorf("expected %d bytes, got %d", sdk.AddrLen, len(bz))
+	}
+	return sdk.AccAddress(bz), nil
+}
+
+func addUnits(units map[string]*big.Rat, denom string, amount *big.Rat) {
+	if amount.Sign() <= 0 {
+		return
+	}
+	if total, ok := units[denom]; ok {
+		total.Add(total, amount)
+	} else {
+		units[denom] = amount
+	}
+}
+
+// the integer part of a rational
+func truncateRat(r *big.Rat) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Quo(r.Num(), r.Denom()))
+}
+
+func decToRat(d sdk.Dec) *big.Rat {
+	return new(big.Rat).SetFrac(d.Int, new(big.Int).Exp(big.NewInt(10), big.NewInt(denomPrecision), nil))
+}
+
+// floorToDec rounds a rational down to the denom precision, so the
+// allocations never exceed the balances
+func floorToDec(r *big.Rat) sdk.Dec {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(denomPrecision), nil)
+	n := new(big.Int).Mul(r.Num(), scale)
+	return sdk.NewDecFromBigIntWithPrec(n.Quo(n, r.Denom()), denomPrecision)
+}
+
+// by denom, then largest first, then by address
+func sortHolders(holders []SnapshotHolder) {
+	sort.Slice(holders, func(i, j int) bool {
+		hi, hj := holders[i], holders[j]
+		if hi.Denom != hj.Denom {
+			return hi.Denom < hj.Denom
+		}
+		if !hi.Amount.Equal(hj.Amount) {
+			return hi.Amount.GT(hj.Amount)
+		}
+		return hi.Address.String() < hj.Address.String()
+	})
+}
+
+// WriteSnapshotCSV writes the holders as a csv allocation file: address,
+// amount, denom, and the exported chain and balances as category and label
+func WriteSnapshotCSV(w io.Writer, snapshot *Snapshot) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"address", "amount", "denom", "category", "label"})
+	for _, h := range snapshot.Holders {
+		cw.Write([]string{h.Address.String(), h.Amount.String(), h.Denom, snapshot.ChainID, h.label()})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSnapshotObject writes the holders as an object allocation file,
+// which holds a single denom
+func WriteSnapshotObject(w io.Writer, snapshot *Snapshot) error {
+	var lines []string
+	for i, h := range snapshot.Holders {
+		if h.Denom != snapshot.Holders[0].Denom {
+			return fmt.Errorf("an object allocation file holds a single denom, got %s and %s, write a csv file",
+				snapshot.Holders[0].Denom, snapshot.Holders[i].Denom)
+		}
+		lines = append(lines, fmt.Sprintf("    %q: %s", h.Address.String(), h.Amount))
+	}
+	_, err := fmt.Fprintf(w, "{\n%s\n}\n", strings.Join(lines, ",\n"))
+	return err
+}
+
+func (h SnapshotHolder) label() string {
+	return fmt.Sprintf("liquid %s, bonded %s, unbonding %s", h.Liquid, h.Bonded, h.Unbonding)
+}
+
+// SnapshotReport sums the kept and dust holders per denom and counts the
+// holders of the denoms left out
+func SnapshotReport(snapshot *Snapshot) []Table {
+	type denomTotal struct {
+		holders, dust  int
+		total, dropped sdk.Dec
+	}
+	totals := make(map[string]*denomTotal)
+	get := func(denom string) *denomTotal {
+		t, ok := totals[denom]
+		if !ok {
+			t = &denomTotal{total: sdk.ZeroDec(), dropped: sdk.ZeroDec()}
+			totals[denom] = t
+		}
+		return t
+	}
+	for _, h := range snapshot.Holders {
+		t := get(h.Denom)
+		t.holders++
+		t.total = t.total.Add(h.Amount)
+	}
+	for _, h := range snapshot.Dust {
+		t := get(h.Denom)
+		t.dust++
+		t.dropped = t.dropped.Add(h.Amount)
+	}
+
+	denoms := Table{Title: "Snapshot", Header: []string{"DENOM", "HOLDERS", "TOTAL", "DUST HOLDERS", "DUST TOTAL"}}
+	names := make([]string, 0, len(totals))
+	for denom := range totals {
+		names = append(names, denom)
+	}
+	sort.Strings(names)
+	for _, denom := range names {
+		t := totals[denom]
+		denoms.Rows = append(denoms.Rows, []string{denom, fmt.Sprint(t.holders), t.total.String(), fmt.Sprint(t.dust), t.dropped.String()})
+	}
+
+	unmapped := Table{Title: "Unmapped denoms", Header: []string{"DENOM", "HOLDERS"}}
+	names = names[:0]
+	for denom := range snapshot.Unmapped {
+		names = append(names, denom)
+	}
+	sort.Strings(names)
+	for _, denom := range names {
+		unmapped.Rows = append(unmapped.Rows, []string{denom, fmt.Sprint(snapshot.Unmapped[denom])})
+	}
+
+	if len(unmapped.Rows) == 0 {
+		return []Table{denoms}
+	}
+	return []Table{denoms, unmapped}
+}
+
+// DustReport lists the holders dropped as dust
+func DustReport(snapshot *Snapshot) []Table {
+	dust := Table{Title: "Dust", Header: []string{"ADDRESS", "DENOM", "AMOUNT", "BALANCES"}}
+	for _, h := range snapshot.Dust {
+		dust.Rows = append(dust.Rows, []string{h.Address.String(), h.Denom, h.Amount.String(), h.label()})
+	}
+	return []Table{dust}
+}
@@ -0,0 +1,147 @@
+package launch
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+)
+
+type jsonObject = map[string]interface{}
+
+func writeTestExport(t *testing.T, appState jsonObject) string {
+	t.Helper()
+	bz, err := json.Marshal(jsonObject{"chain_id": "old-chain", "app_state": appState})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeTestFile(t, "export.json", string(bz))
+}
+
+func testCoins(denomAmounts ...string) []jsonObject {
+	var coins []jsonObject
+	for i := 0; i < len(denomAmounts); i += 2 {
+		coins = append(coins, jsonObject{"denom": denomAmounts[i], "amount": denomAmounts[i+1]})
+	}
+	return coins
+}
+
+func testModuleAddress(t *testing.T, name string) string {
+	return testBech32(t, "cosmos", string(tmhash.SumTruncated([]byte(name))))
+}
+
+type snapshotRow struct {
+	name, denom, amount string
+}
+
+func snapshotRows(holders []SnapshotHolder, names map[string]string) []snapshotRow {
+	var rows []snapshotRow
+	for _, h := range holders {
+		rows = append(rows, snapshotRow{names[h.Address.String()], h.Denom, h.Amount.String()})
+	}
+	return rows
+}
+
+func TestTakeSnapshot(t *testing.T) {
+	alice, bob, carol := testBech32(t, "cosmos", "alice_______________"), testBech32(t, "cosmos", "bob_________________"),
+		testBech32(t, "cosmos", "carol_______________")
+	val := testBech32(t, "cosmosvaloper", "val_________________")
+	names := map[string]string{
+		testAccount("alice").String(): "alice", testAccount("bob").String(): "bob", testAccount("carol").String(): "carol",
+	}
+
+	// okchaind exports the accounts as a plain list, module accounts included
+	okchaind := jsonObject{
+		"accounts": []jsonObject{
+			{"address": alice, "coins": testCoins("okb", "100.5")},
+			{"address": bob, "coins": testCoins("okb", "30", "xyz", "7")},
+			{"address": testModuleAddress(t, "bonded_tokens_pool"), "coins": testCoins("okb", "1000")},
+			{"address": carol, "coins": testCoins("okb", "50"), "module_name": "gov"},
+		},
+		"staking": jsonObject{
+			"params":     jsonObject{"bond_denom": "okb"},
+			"validators": []jsonObject{{"operator_address": val, "tokens": "200", "delegator_shares": "100"}},
+			"delegations": []jsonObject{
+				{"delegator_address": alice, "validator_address": val, "shares": "10"},
+			},
+			"unbonding_delegations": []jsonObject{
+				{"delegator_address": bob, "entries": []jsonObject{{"balance": "5"}}},
+			},
+		},
+	}
+	// cosmos-sdk exports amino wrapped auth accounts
+	cosmos := jsonObject{
+		"auth": jsonObject{"accounts": []jsonObject{
+			{"type": "cosmos-sdk/Account", "value": jsonObject{"address": alice, "coins": testCoins("uatom", "1500000")}},
+			{"type": "cosmos-sdk/ContinuousVestingAccount", "value": jsonObject{"BaseVestingAccount": jsonObject{
+				"BaseAccount": jsonObject{"address": bob, "coins": testCoins("uatom", "2000000", "ufoo", "1")},
+			}}},
+			{"type": "cosmos-sdk/ModuleAccount", "value": jsonObject{
+				"BaseAccount": jsonObject{"address": testBech32(t, "cosmos", "pool________________"), "coins": testCoins("uatom", "9000000")},
+				"name":        "not_bonded_tokens_pool",
+			}},
+		}},
+	}
+
+	cases := []struct {
+		name     string
+		appState jsonObject
+		cfg      SnapshotConfig
+		holders  []snapshotRow
+		dust     []snapshotRow
+		modules  []string
+		unmapped map[string]int
+		staking  bool
+	}{
+		{"okchaind", okchaind, SnapshotConfig{Ratio: sdk.NewDec(2), Threshold: sdk.NewDec(50)},
+			[]snapshotRow{{"alice", "okb", "240.00000000"}, {"bob", "okb", "70.00000000"}},
+			[]snapshotRow{{"bob", "xyz", "14.00000000"}},
+			[]string{"bonded_tokens_pool", "gov"}, map[string]int{}, true},
+		{"cosmos", cosmos, SnapshotConfig{Decimals: 6, Ratio: sdk.OneDec(), Threshold: sdk.ZeroDec(), Denoms: map[string]string{"uatom": "okb"}},
+			[]snapshotRow{{"bob", "okb", "2.00000000"}, {"alice", "okb", "1.50000000"}}, nil,
+			[]string{"not_bonded_tokens_pool"}, map[string]int{"ufoo": 1}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			snapshot, err := TakeSnapshot(writeTestExport(t, tc.appState), tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := snapshotRows(snapshot.Holders, names); !reflect.DeepEqual(got, tc.holders) {
+				t.Errorf("expected holders %v, got %v", tc.holders, got)
+			}
+			if got := snapshotRows(snapshot.Dust, names); !reflect.DeepEqual(got, tc.dust) {
+				t.Errorf("expected dust %v, got %v", tc.dust, got)
+			}
+			if !reflect.DeepEqual(snapshot.Modules, tc.modules) {
+				t.Errorf("expected module accounts %v, got %v", tc.modules, snapshot.Modules)
+			}
+			if !reflect.DeepEqual(snapshot.Unmapped, tc.unmapped) {
+				t.Errorf("expected unmapped denoms %v, got %v", tc.unmapped, snapshot.Unmapped)
+			}
+			if snapshot.Staking != tc.staking || snapshot.ChainID != "old-chain" {
+				t.Errorf("unexpected snapshot of %s, staking %v", snapshot.ChainID, snapshot.Staking)
+			}
+		})
+	}
+}
+
+func TestTakeSnapshotMissingValidator(t *testing.T) {
+	alice := testBech32(t, "cosmos", "alice_______________")
+	file := writeTestExport(t, jsonObject{
+		"accounts": []jsonObject{{"address": alice, "coins": testCoins("okb", "1")}},
+		"staking": jsonObject{
+			"validators": []jsonObject{},
+			"delegations": []jsonObject{
+				{"delegator_address": alice, "validator_address": testBech32(t, "cosmosvaloper", "gone________________"), "shares": "10"},
+			},
+		},
+	})
+	_, err := TakeSnapshot(file, SnapshotConfig{Ratio: sdk.OneDec(), Threshold: sdk.ZeroDec()})
+	if err == nil || !strings.Contains(err.Error(), "isn't exported") {
+		t.Errorf("expected the delegation to a missing validator to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package launch
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+)
+
+// sourceTemplate labels the accounts that were already in the template
+const sourceTemplate = "template"
+
+// SourceTotal is the amount of a denom allocated by one input
+type SourceTotal struct {
+	Source string
+	Amount sdk.Dec
+}
+
+// ErrSupplyMismatch is returned when the accounts don't add up to a token total supply
+type ErrSupplyMismatch struct {
+	Denom    string
+	Supply   sdk.Dec
+	Total    sdk.Dec
+	BySource []SourceTotal
+}
+
+func (e ErrSupplyMismatch) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "total supply of %s is %v but the accounts hold %v", e.Denom, e.Supply, e.Total)
+	for _, st := range e.BySource {
+		fmt.Fprintf(&sb, "\n  %-40s %v", st.Source, st.Amount)
+	}
+	return sb.String()
+}
+
+// ErrPoolMismatch is returned when the not bonded pool doesn't match the bond denom held by the accounts
+type ErrPoolMismatch struct {
+	Denom string
+	Pool  sdk.Int
+	Total sdk.Int
+}
+
+func (e ErrPoolMismatch) Error() string {
+	return fmt.Sprintf("not bonded pool is %v but the accounts hold %v (units of %s)", e.Pool, e.Total, e.Denom)
+}
+
+// supplyBySource sums the accounts coins per denom and per source file
+func (b *Builder) supplyBySource(accounts []app.GenesisAccount) map[string]map[string]sdk.Dec {
+	totals := make(map[string]map[string]sdk.Dec)
+	for _, acc := range accounts {
+		for _, coin := range acc.Coins {
+			source, ok := b.sources[sourceKey(coin.Denom, acc.Address.String())]
+			if !ok {
+				source = sourceTemplate
+			}
+			if totals[coin.Denom] == nil {
+				totals[coin.Denom] = make(map[string]sdk.Dec)
+			}
+			if amt, ok := totals[coin.Denom][source]; ok {
+				totals[coin.Denom][source] = amt.Add(coin.Amount)
+			} else {
+				totals[coin.Denom][source] = coin.Amount
+			}
+		}
+	}
+	return totals
+}
+
+// CheckSupply reconciles the genesis accounts with the token total supplies
+// and the staking pool: every denom held must be a genesis token whose total
+// supply is exactly the sum of the balances, and the not bonded pool must hold
+// the whole bond denom, as no gentx is delivered yet.
+func (b *Builder) CheckSupply(genesisState app.GenesisState) error {
+	totals := b.supplyBySource(genesisState.Accounts)
+
+	supplies := make(map[string]sdk.Dec, len(genesisState.Token.Info))
+	for _, t := range genesisState.Token.Info {
+		supplies[t.Symbol] = sdk.NewDec(t.TotalSupply)
+	}
+	denoms := make([]string, 0, len(totals)+len(supplies))
+	for denom := range supplies {
+		denoms = append(denoms, denom)
+	}
+	for denom := range totals {
+		if _, ok := supplies[denom]; !ok {
+			denoms = append(denoms, denom)
+		}
+	}
+	sort.Strings(denoms)
+
+	for _, denom := range denoms {
+		var bySource []SourceTotal
+		total := sdk.ZeroDec()
+		for source, amt := range totals[denom] {
+			bySource = append(bySource, SourceTotal{source, amt})
+			total = total.Add(amt)
+		}
+		sort.Slice(bySource, func(i, j int) bool { return bySource[i].Source < bySource[j].Source })
+
+		supply, ok := supplies[denom]
+		if !ok {
+			supply = sdk.ZeroDec()
+		}
+		if !supply.Equal(total) {
+			return ErrSupplyMismatch{denom, supply, total, bySource}
+		}
+	}
+
+	bondDenom := genesisState.StakingData.Params.BondDenom
+	bonded := sdk.ZeroInt()
+	for _, acc := range genesisState.Accounts {
+		bonded = bonded.Add(sdk.NewIntFromBigInt(acc.Coins.AmountOf(bondDenom).Int))
+	}
+	if pool := genesisState.StakingData.Pool.NotBondedTokens; !pool.Equal(bonded) {
+		return ErrPoolMismatch{bondDenom, pool, bonded}
+	}
+	return nil
+}
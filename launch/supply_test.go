@@ -0,0 +1,130 @@
+package launch
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/token"
+)
+
+func TestCheckSupply(t *testing.T) {
+	alice := sdk.AccAddress([]byte("alice_______________"))
+	bob := sdk.AccAddress([]byte("bob_________________"))
+	account := func(addr sdk.AccAddress, coins ...sdk.DecCoin) app.GenesisAccount {
+		return app.GenesisAccount{Address: addr, Coins: sdk.DecCoins(coins).Sort()}
+	}
+	okb := func(amt string) sdk.DecCoin { return sdk.NewDecCoinFromDec("okb", sdk.MustNewDecFromStr(amt)) }
+	xyz := func(amt string) sdk.DecCoin { return sdk.NewDecCoinFromDec("xyz", sdk.MustNewDecFromStr(amt)) }
+
+	cases := []struct {
+		name     string
+		accounts []app.GenesisAccount
+		tokens   []token.Token
+		denom    string // denom of the expected ErrSupplyMismatch, empty when the supply adds up
+	}{
+		{"exact", []app.GenesisAccount{account(alice, okb("600")), account(bob, okb("400"))},
+			[]token.Token{{Symbol: "okb", TotalSupply: 1000}}, ""},
+		{"fractions add up", []app.GenesisAccount{account(alice, okb("0.5")), account(bob, okb("99.5"))},
+			[]token.Token{{Symbol: "okb", TotalSupply: 100}}, ""},
+		{"over allocated", []app.GenesisAccount{account(alice, okb("600")), account(bob, okb("500"))},
+			[]token.Token{{Symbol: "okb", TotalSupply: 1000}}, "okb"},
+		{"under allocated", []app.GenesisAccount{account(alice, okb("999.99999999"))},
+			[]token.Token{{Symbol: "okb", TotalSupply: 1000}}, "okb"},
+		{"denom without token", []app.GenesisAccount{account(alice, okb("1000"), xyz("1"))},
+			[]token.Token{{Symbol: "okb", TotalSupply: 1000}}, "xyz"},
+		{"token without holders", []app.GenesisAccount{account(alice, okb("1000"))},
+			[]token.Token{{Symbol: "okb", TotalSupply: 1000}, {Symbol: "xyz", TotalSupply: 5}}, "xyz"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := stakedState(tc.accounts)
+			state.Token.Info = tc.tokens
+			err := (&Builder{}).CheckSupply(state)
+			if tc.denom == "" {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			mismatch, ok := err.(ErrSupplyMismatch)
+			if !ok {
+				t.Fatalf("expected ErrSupplyMismatch, got %v", err)
+			}
+			if mismatch.Denom != tc.denom {
+				t.Errorf("expected a mismatch of %s, got %s", tc.denom, mismatch.Denom)
+			}
+		})
+	}
+}
+
+// stakedState holds the accounts with the whole okb supply in the not bonded pool
+func stakedState(accounts []app.GenesisAccount) app.GenesisState {
+	var state app.GenesisState
+	state.Accounts = accounts
+	state.StakingData.Params.BondDenom = okbDenomination
+	state.StakingData.Pool.NotBondedTokens = sdk.ZeroInt()
+	for _, acc := range accounts {
+		state.StakingData.Pool.NotBondedTokens = state.StakingData.Pool.NotBondedTokens.Add(
+			sdk.NewIntFromBigInt(acc.Coins.AmountOf(okbDenomination).Int))
+	}
+	return state
+}
+
+func TestCheckSupplyPool(t *testing.T) {
+	alice := sdk.AccAddress([]byte("alice_______________"))
+	accounts := []app.GenesisAccount{{Address: alice, Coins: newCoins(okbDenomination, sdk.NewDec(10))}}
+	units := sdk.NewIntWithDecimal(10, sdk.Precision)
+
+	cases := []struct {
+		name string
+		pool sdk.Int
+		ok   bool
+	}{
+		{"whole supply", units, true},
+		{"empty pool", sdk.ZeroInt(), false},
+		{"whole tokens instead of units", sdk.NewInt(10), false},
+		{"one unit over", units.AddRaw(1), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := stakedState(accounts)
+			state.Token.Info = []token.Token{{Symbol: okbDenomination, TotalSupply: 10}}
+			state.StakingData.Pool.NotBondedTokens = tc.pool
+			err := (&Builder{}).CheckSupply(state)
+			if tc.ok != (err == nil) {
+				t.Fatalf("ok=%v, got err %v", tc.ok, err)
+			}
+			if _, isPool := err.(ErrPoolMismatch); !tc.ok && !isPool {
+				t.Errorf("expected ErrPoolMismatch, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckSupplyBySource(t *testing.T) {
+	alice := sdk.AccAddress([]byte("alice_______________"))
+	bob := sdk.AccAddress([]byte("bob_________________"))
+	b := &Builder{sources: map[string]string{sourceKey("okb", alice.String()): "accounts/others.json"}}
+
+	state := stakedState([]app.GenesisAccount{
+		{Address: alice, Coins: sdk.DecCoins{sdk.NewDecCoinFromDec("okb", sdk.NewDec(7))}},
+		{Address: bob, Coins: sdk.DecCoins{sdk.NewDecCoinFromDec("okb", sdk.NewDec(2))}},
+	})
+	state.Token.Info = []token.Token{{Symbol: "okb", TotalSupply: 10}}
+
+	err := b.CheckSupply(state)
+	mismatch, ok := err.(ErrSupplyMismatch)
+	if !ok {
+		t.Fatalf("expected ErrSupplyMismatch, got %v", err)
+	}
+	want := []SourceTotal{{"accounts/others.json", sdk.NewDec(7)}, {sourceTemplate, sdk.NewDec(2)}}
+	if len(mismatch.BySource) != len(want) {
+		t.Fatalf("expected %d sources, got %v", len(want), mismatch.BySource)
+	}
+	for i, st := range want {
+		if mismatch.BySource[i].Source != st.Source || !mismatch.BySource[i].Amount.Equal(st.Amount) {
+			t.Errorf("source %d: expected %v, got %v", i, st, mismatch.BySource[i])
+		}
+	}
+}
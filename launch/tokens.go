@@ -0,0 +1,173 @@
+package launch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/x/token"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// TokensFile lists the genesis tokens, replacing the template ones,
+// and the trading pairs to list once the chain runs
+type TokensFile struct {
+	Tokens []TokenSpec `yaml:"tokens"`
+	Pairs  []PairSpec  `yaml:"pairs"`
+}
+
+// TokenSpec is a genesis token. The okb holders come from the manifest
+// sources, the holders of any other token from its allocations file.
+type TokenSpec struct {
+	Name        string `yaml:"name"`
+	Symbol      string `yaml:"symbol"`
+	Supply      string `yaml:"supply"` // whole tokens
+	Owner       string `yaml:"owner"`  // defaults to the first account, the captain
+	Mintable    bool   `yaml:"mintable"`
+	Allocations string `yaml:"allocations"`
+	Format      string `yaml:"format"` // of the allocations file, list or object
+}
+
+// PairSpec is a base/quote trading pair
+type PairSpec struct {
+	Base          string `yaml:"base"`
+	Quote         string `yaml:"quote"`
+	Price         string `yaml:"price"`
+	MaxPriceDigit int64  `yaml:"max_price_digit"`
+	MaxSizeDigit  int64  `yaml:"max_size_digit"`
+	MinTradeSize  string `yaml:"min_trade_size"`
+}
+
+// AddTokensFile loads the genesis tokens with their allocations and the
+// trading pairs. Symbols must be valid coin names and every pair must
+// trade genesis tokens.
+func (b *Builder) AddTokensFile(file string) error {
+	var tf TokensFile
+	bz, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ErrBadFile{file, err}
+	}
+	if err := yaml.UnmarshalStrict(bz, &tf); err != nil {
+		return ErrBadFile{file, err}
+	}
+	if len(tf.Tokens) == 0 {
+		return ErrBadFile{file, fmt.Errorf("no token")}
+	}
+
+	symbols := make(map[string]bool, len(tf.Tokens))
+	for _, spec := range tf.Tokens {
+		t, err := spec.token()
+		if err != nil {
+			return ErrBadFile{file, err}
+		}
+		if symbols[t.Symbol] {
+			return ErrBadFile{file, fmt.Errorf("duplicate token %s", t.Symbol)}
+		}
+		symbols[t.Symbol] = true
+		if err := b.addTokenAllocations(spec); err != nil {
+			return err
+		}
+		b.tokens = append(b.tokens, t)
+	}
+
+	pairs := make(map[string]bool, len(tf.Pairs))
+	for _, spec := range tf.Pairs {
+		pair, err := spec.pair(symbols)
+		if err != nil {
+			return ErrBadFile{file, err}
+		}
+		name := pair.BaseAssetSymbol + "_" + pair.QuoteAssetSymbol
+		if pairs[name] {
+			return ErrBadFile{file, fmt.Errorf("duplicate pair %s", name)}
+		}
+		pairs[name] = true
+		b.pairs = append(b.pairs, pair)
+	}
+	return nil
+}
+
+func (spec TokenSpec) token() (token.Token, error) {
+	t := token.Token{Name: spec.Name, Symbol: spec.Symbol, Mintable: spec.Mintable}
+	if !token.ValidCoinName(spec.Symbol) {
+		return t, fmt.Errorf("bad token symbol %q: expected [a-z][a-z0-9]{2,15}", spec.Symbol)
+	}
+	if spec.Name == "" {
+		return t, fmt.Errorf("token %s has no name", spec.Symbol)
+	}
+	supply, err := strconv.ParseInt(spec.Supply, 10, 64)
+	if err != nil || supply <= 0 {
+		return t, fmt.Errorf("token %s: bad supply %q, expected a positive count of whole tokens", spec.Symbol, spec.Supply)
+	}
+	t.TotalSupply = supply
+	if spec.Owner != "" {
+		if t.Owner, err = fromBech32(spec.Owner); err != nil {
+			return t, fmt.Errorf("token %s: bad owner %s: %v", spec.Symbol, spec.Owner, err)
+		}
+	}
+	return t, nil
+}
+
+// load the holders of a token, okb ones come from the manifest
+func (b *Builder) addTokenAllocations(spec TokenSpec) error {
+	if spec.Allocations == "" {
+		return nil
+	}
+	if spec.Symbol == okbDenomination {
+		return ErrBadFile{spec.Allocations, fmt.Errorf("%s is allocated by the manifest sources", okbDenomination)}
+	}
+
+	var allocs []Allocation
+	var err error
+	switch spec.Format {
+	case FormatList:
+		allocs, err = ReadListAllocations(spec.Allocations)
+	case FormatObject, "":
+		allocs, err = ReadObjectAllocations(spec.Allocations)
+	default:
+		err = ErrBadFile{spec.Allocations, fmt.Errorf("unknown format %q for token %s", spec.Format, spec.Symbol)}
+	}
+	if err != nil {
+		return err
+	}
+	for i := range allocs {
+		allocs[i].Denom = spec.Symbol
+	}
+	return b.AddAllocations(allocs)
+}
+
+func (spec PairSpec) pair(symbols map[string]bool) (token.TokenPair, error) {
+	pair := token.TokenPair{
+		BaseAssetSymbol:  spec.Base,
+		QuoteAssetSymbol: spec.Quote,
+		MaxPriceDigit:    spec.MaxPriceDigit,
+		MaxQuantityDigit: spec.MaxSizeDigit,
+	}
+	name := spec.Base + "_" + spec.Quote
+	if !symbols[spec.Base] || !symbols[spec.Quote] {
+		return pair, fmt.Errorf("pair %s: both sides must be genesis tokens", name)
+	}
+	if spec.Base == spec.Quote {
+		return pair, fmt.Errorf("pair %s: base and quote are the same token", name)
+	}
+	var err error
+	if pair.InitPrice, err = ParseAmount(json.Number(spec.Price), denomPrecision); err != nil || !pair.InitPrice.IsPositive() {
+		return pair, fmt.Errorf("pair %s: bad price %q", name, spec.Price)
+	}
+	if pair.MinQuantity, err = ParseAmount(json.Number(spec.MinTradeSize), denomPrecision); err != nil || !pair.MinQuantity.IsPositive() {
+		return pair, fmt.Errorf("pair %s: bad min_trade_size %q", name, spec.MinTradeSize)
+	}
+	for _, digits := range []int64{spec.MaxPriceDigit, spec.MaxSizeDigit} {
+		if digits < 0 || digits > sdk.Precision {
+			return pair, fmt.Errorf("pair %s: digits must be between 0 and %d", name, sdk.Precision)
+		}
+	}
+	return pair, nil
+}
+
+// TradingPairs returns the pairs of the tokens file. The token module has
+// no genesis state for them, they are listed once the chain runs.
+func (b *Builder) TradingPairs() []token.TokenPair {
+	return b.pairs
+}
@@ -0,0 +1,195 @@
+package launch
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	decType      = reflect.TypeOf(sdk.Dec{})
+	decCoinsType = reflect.TypeOf(sdk.DecCoins{})
+
+	// a count of weeks or days, optionally followed by a go duration
+	reDays = regexp.MustCompile(`^(\d+)([wd])(.*)$`)
+	// an amount followed by its denom
+	reCoin = regexp.MustCompile(`^([0-9.]+)\s*([a-z][a-z0-9-]*)$`)
+
+	// the decimal params holding an amount of the native denom, the other
+	// ones are fractions (rates, slash fractions)
+	amountParams = map[string]bool{
+		"token.list_asset":                true,
+		"token.issue_asset":               true,
+		"token.mint_asset":                true,
+		"token.burn_asset":                true,
+		"token.transfer":                  true,
+		"token.freeze_asset":              true,
+		"token.unfreeze_asset":            true,
+		"token.list_proposal_min_deposit": true,
+		"order.new_order":                 true,
+		"order.cancel":                    true,
+		"order.cancel_native":             true,
+		"order.expire":                    true,
+		"order.expire_native":             true,
+	}
+)
+
+// paramJSON converts a profile value to the amino json of a param of type t.
+// Besides the amino json itself, values can be given in human units:
+// durations (6d, 2w, 24h, 1h30m), decimals (0.13 or 13% for a fraction,
+// 20000okb for an amount param), coins (20000okb) and plain integers.
+func paramJSON(t reflect.Type, amount bool, value interface{}) (json.RawMessage, error) {
+	switch {
+	case t == durationType:
+		d, err := parseDuration(fmt.Sprint(value))
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(strconv.FormatInt(int64(d), 10))
+	case t == decType:
+		dec, err := parseDecParam(formatNumber(value), amount)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(dec)
+	case t == decCoinsType:
+		s, ok := value.(string)
+		if !ok {
+			break
+		}
+		coins, err := parseDecCoins(s)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(coins)
+	}
+
+	// amino writes 64 bit integers as strings
+	switch t.Kind() {
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(fmt.Sprint(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad integer %v", value)
+		}
+		return json.Marshal(strconv.FormatInt(n, 10))
+	case reflect.Uint, reflect.Uint64:
+		n, err := strconv.ParseUint(fmt.Sprint(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad integer %v", value)
+		}
+		return json.Marshal(strconv.FormatUint(n, 10))
+	}
+	return json.Marshal(jsonValue(value))
+}
+
+// parseDuration reads nanoseconds, a go duration or weeks and days
+// followed by an optional go duration, e.g. 6d, 1d12h. Durations can't be
+// negative.
+func parseDuration(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-") {
+		return 0, fmt.Errorf("bad duration %q: negative", s)
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(n), nil
+	}
+
+	var d time.Duration
+	rest := s
+	if m := reDays.FindStringSubmatch(s); m != nil {
+		n, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("bad duration %q: %v", s, err)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+		if m[2] == "w" {
+			d *= 7
+		}
+		if rest = m[3]; rest == "" {
+			return d, nil
+		}
+	}
+	goDuration, err := time.ParseDuration(rest)
+	if err != nil || goDuration < 0 {
+		return 0, fmt.Errorf("bad duration %q: expected nanoseconds, <n>(w|d) or a go duration like 24h", s)
+	}
+	return d + goDuration, nil
+}
+
+// parseDecParam reads a decimal param. Fractions are given as a decimal or
+// a percentage, amounts as a decimal or an amount of the native denom, e.g.
+// 20000okb.
+func parseDecParam(s string, amount bool) (sdk.Dec, error) {
+	if !amount {
+		return parseDecimal(s)
+	}
+	m := reCoin.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		if strings.HasSuffix(strings.TrimSpace(s), "%") {
+			return sdk.Dec{}, fmt.Errorf("bad amount %q: not a fraction", s)
+		}
+		return parseDecimal(s)
+	}
+	if m[2] != okbDenomination {
+		return sdk.Dec{}, fmt.Errorf("bad decimal %q: only %s amounts convert to a decimal", s, okbDenomination)
+	}
+	return parseDecimal(m[1])
+}
+
+// parseDecimal reads an exact decimal, or a percentage of one, e.g. 13%
+func parseDecimal(s string) (sdk.Dec, error) {
+	s = strings.TrimSpace(s)
+	num := strings.TrimSuffix(s, "%")
+	if !reAmount.MatchString(num) {
+		return sdk.Dec{}, fmt.Errorf("bad decimal %q", s)
+	}
+	r, ok := new(big.Rat).SetString(num)
+	if !ok {
+		return sdk.Dec{}, fmt.Errorf("bad decimal %q", s)
+	}
+	if num != s {
+		r.Quo(r, big.NewRat(100, 1))
+	}
+	dec, err := ratToDec(r, denomPrecision)
+	if err != nil {
+		return sdk.Dec{}, fmt.Errorf("bad decimal %q: %v", s, err)
+	}
+	return dec, nil
+}
+
+// parseDecCoins reads comma separated amounts followed by their denom, e.g. 20000okb
+func parseDecCoins(s string) (sdk.DecCoins, error) {
+	var coins sdk.DecCoins
+	for _, coinString := range strings.Split(s, ",") {
+		m := reCoin.FindStringSubmatch(strings.TrimSpace(coinString))
+		if m == nil {
+			return nil, fmt.Errorf("bad coins %q: expected <amount><denom>, e.g. 20000okb", s)
+		}
+		amount, err := parseDecimal(m[1])
+		if err != nil {
+			return nil, err
+		}
+		coins = append(coins, sdk.NewDecCoinFromDec(m[2], amount))
+	}
+	coins = coins.Sort()
+	if !coins.IsValid() {
+		return nil, fmt.Errorf("bad coins %q: duplicate or non positive denoms", s)
+	}
+	return coins, nil
+}
+
+// YAML numbers come as ints and floats, write them without an exponent
+func formatNumber(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,167 @@
+package launch
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/ok-chain/okchain/app"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"1000", 1000, true},
+		{"24h", 24 * time.Hour, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"6d", 6 * 24 * time.Hour, true},
+		{"3w", 21 * 24 * time.Hour, true},
+		{"1d12h", 36 * time.Hour, true},
+		{" 2w ", 14 * 24 * time.Hour, true},
+		{"1y", 0, false},
+		{"d", 0, false},
+		{"1d12", 0, false},
+		{"-5", 0, false},
+		{"-1h", 0, false},
+		{"-1d", 0, false},
+		{"1d-1h", 0, false},
+		{"", 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := parseDuration(tc.in)
+			if tc.ok != (err == nil) {
+				t.Fatalf("ok=%v, got err %v", tc.ok, err)
+			}
+			if tc.ok && got != tc.want {
+				t.Errorf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseDecParam(t *testing.T) {
+	cases := []struct {
+		in     string
+		amount bool
+		want   string // empty when the value must be rejected
+	}{
+		{"0.13", false, "0.13000000"},
+		{"13%", false, "0.13000000"},
+		{"0.5%", false, "0.00500000"},
+		{"0.12345678", false, "0.12345678"},
+		{"0.123456789", false, ""},
+		{"0.000001%", false, "0.00000001"},
+		{"0.0000001%", false, ""},
+		{"0.05okb", false, ""},
+		{"1e3", false, ""},
+		{"1/4", false, ""},
+		{"0x10", false, ""},
+		{"-1", false, ""},
+		{"", false, ""},
+		{"20000", true, "20000.00000000"},
+		{"20000okb", true, "20000.00000000"},
+		{"0.5 okb", true, "0.50000000"},
+		{"20000xyz", true, ""},
+		{"13%", true, ""},
+		{"13%okb", true, ""},
+		{"-1okb", true, ""},
+		{"okb", true, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := parseDecParam(tc.in, tc.amount)
+			if (tc.want != "") != (err == nil) {
+				t.Fatalf("expected %q, got err %v", tc.want, err)
+			}
+			if err == nil && got.String() != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseDecCoins(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string // empty when the coins must be rejected
+	}{
+		{"20000okb", "20000.00000000okb"},
+		{"1.5okb, 2xyz", "1.50000000okb,2.00000000xyz"},
+		{"2xyz,1okb", "1.00000000okb,2.00000000xyz"},
+		{"0okb", ""},
+		{"1okb,2okb", ""},
+		{"20000", ""},
+		{"1e3 okb", ""},
+		{"okb", ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := parseDecCoins(tc.in)
+			if (tc.want != "") != (err == nil) {
+				t.Fatalf("expected %q, got err %v", tc.want, err)
+			}
+			if err == nil && got.String() != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParamJSON(t *testing.T) {
+	cases := []struct {
+		name   string
+		t      reflect.Type
+		amount bool
+		value  interface{}
+		want   string // empty when the value must be rejected
+	}{
+		{"duration", durationType, false, "3w", `"1814400000000000"`},
+		{"duration nanoseconds", durationType, false, 60000000000, `"60000000000"`},
+		{"negative duration", durationType, false, "-1h", ""},
+		{"decimal", decType, false, 0.13, `"0.13000000"`},
+		{"percentage", decType, false, "13%", `"0.13000000"`},
+		{"fraction in okb", decType, false, "0.01okb", ""},
+		{"native amount", decType, true, "20000okb", `"20000.00000000"`},
+		{"other denom", decType, true, "20000xyz", ""},
+		{"coins", decCoinsType, false, "100okb", `[{"denom":"okb","amount":"100.00000000"}]`},
+		{"int64", reflect.TypeOf(int64(0)), false, 10000, `"10000"`},
+		{"bad int64", reflect.TypeOf(int64(0)), false, "1k", ""},
+		{"uint16", reflect.TypeOf(uint16(0)), false, 100, `100`},
+		{"bool", reflect.TypeOf(false), false, true, `true`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := paramJSON(tc.t, tc.amount, tc.value)
+			if (tc.want != "") != (err == nil) {
+				t.Fatalf("expected %s, got err %v", tc.want, err)
+			}
+			if err == nil && string(raw) != tc.want {
+				t.Errorf("expected %s, got %s", tc.want, raw)
+			}
+		})
+	}
+}
+
+func TestProfileIssueAsset(t *testing.T) {
+	state := app.NewDefaultGenesisState()
+	p := Profile{File: "test.yaml", Params: map[string]map[string]interface{}{
+		"token": {"issue_asset": "20000okb"},
+	}}
+	if err := p.Apply(&state); err != nil {
+		t.Fatal(err)
+	}
+	fraction := Profile{File: "test.yaml", Params: map[string]map[string]interface{}{
+		"slashing": {"slash_fraction_downtime": "0.01okb"},
+	}}
+	if err := fraction.Apply(&state); err == nil {
+		t.Error("expected an okb amount to be rejected for a fraction")
+	}
+	if want := sdk.NewDec(20000); !state.Token.Params.IssueAsset.Equal(want) {
+		t.Errorf("expected issue_asset %v, got %v", want, state.Token.Params.IssueAsset)
+	}
+}
@@ -0,0 +1,115 @@
+package launch
+
+import (
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth"
+	"github.com/cosmos/cosmos-sdk/x/bank"
+	"github.com/cosmos/cosmos-sdk/x/mint"
+	"github.com/cosmos/cosmos-sdk/x/slashing"
+	"github.com/ok-chain/okchain/app"
+	distr "github.com/ok-chain/okchain/x/distribution"
+	"github.com/ok-chain/okchain/x/gov"
+	"github.com/ok-chain/okchain/x/order"
+	"github.com/ok-chain/okchain/x/staking"
+	"github.com/ok-chain/okchain/x/token"
+)
+
+// module name of the launch rules in the validation failures
+const launchRules = "launch"
+
+// ValidationFailure is a failed check of one section of the app state
+type ValidationFailure struct {
+	Module string
+	Err    error
+}
+
+// ErrInvalidGenesis is returned when the app state fails its checks
+type ErrInvalidGenesis struct {
+	Failures []ValidationFailure
+}
+
+func (e ErrInvalidGenesis) Error() string {
+	lines := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		lines = append(lines, fmt.Sprintf("%s: %s", f.Module, oneLine(f.Err)))
+	}
+	return fmt.Sprintf("invalid genesis state, %d failed check(s):\n  %s", len(lines), strings.Join(lines, "\n  "))
+}
+
+// ValidateGenesisState runs the ValidateGenesis of every module, which
+// app.ValidateGenesisState skips when there are gentxs, then the launch rules.
+// Every failure is reported, not only the first one.
+func ValidateGenesisState(genesisState app.GenesisState) error {
+	var failures []ValidationFailure
+	check := func(module string, err error) {
+		if err != nil {
+			failures = append(failures, ValidationFailure{module, err})
+		}
+	}
+
+	check("auth", auth.ValidateGenesis(genesisState.AuthData))
+	check("bank", bank.ValidateGenesis(genesisState.BankData))
+	check("staking", staking.ValidateGenesis(genesisState.StakingData))
+	check("mint", mint.ValidateGenesis(genesisState.MintData))
+	check("distr", distr.ValidateGenesis(genesisState.DistrData))
+	check("gov", gov.ValidateGenesis(genesisState.GovData))
+	check("slashing", slashing.ValidateGenesis(genesisState.SlashingData))
+	check("order", order.ValidateGenesis(genesisState.Order))
+	check("token", token.ValidateGenesis(genesisState.Token))
+
+	for _, err := range checkLaunchRules(genesisState) {
+		check(launchRules, err)
+	}
+
+	if len(failures) > 0 {
+		return ErrInvalidGenesis{failures}
+	}
+	return nil
+}
+
+// checkLaunchRules checks what the modules take for granted: tokens have
+// an owner holding some of them and every denom the params refer to is a
+// genesis token
+func checkLaunchRules(genesisState app.GenesisState) []error {
+	var errs []error
+	balances := make(map[string]sdk.DecCoins, len(genesisState.Accounts))
+	for _, acc := range genesisState.Accounts {
+		balances[acc.Address.String()] = acc.Coins
+	}
+	denoms := make(map[string]bool)
+	for _, t := range genesisState.Token.Info {
+		if t.Owner.Empty() {
+			errs = append(errs, fmt.Errorf("token %s has no owner", t.Symbol))
+		} else if !balances[t.Owner.String()].AmountOf(t.Symbol).IsPositive() {
+			// the chain would issue the whole supply to the owner a second time
+			errs = append(errs, fmt.Errorf("token %s owner %s holds none of it", t.Symbol, t.Owner))
+		}
+		denoms[t.Symbol] = true
+	}
+
+	bondDenom := genesisState.StakingData.Params.BondDenom
+	if !denoms[bondDenom] {
+		errs = append(errs, fmt.Errorf("bond denom %q is not a genesis token", bondDenom))
+	}
+
+	govParams := genesisState.GovData.Params
+	for _, p := range []struct {
+		name  string
+		coins sdk.DecCoins
+	}{
+		{"min_deposit", govParams.MinDeposit},
+		{"dex_list_min_deposit", govParams.DexListMinDeposit},
+		{"dex_list_vote_fee", govParams.DexListVoteFee},
+		{"dex_list_fee", govParams.DexListFee},
+	} {
+		for _, coin := range p.coins {
+			if !denoms[coin.Denom] {
+				errs = append(errs, fmt.Errorf("gov %s %v is not in a genesis token", p.name, coin))
+			}
+		}
+	}
+	return errs
+}
@@ -0,0 +1,158 @@
+package launch
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ok-chain/okchain/app"
+	"github.com/ok-chain/okchain/x/common"
+)
+
+// LockTime is either an absolute time or an offset from the genesis time
+type LockTime struct {
+	Time time.Time // absolute time, zero when relative
+
+	// offset from genesis, applied with AddDate then Add
+	Years, Months, Days int
+	Duration            time.Duration
+}
+
+// Relative is true when the lock time is an offset from genesis
+func (t LockTime) Relative() bool {
+	return t.Time.IsZero()
+}
+
+// Resolve returns the lock time for the given genesis time
+func (t LockTime) Resolve(genesisTime time.Time) time.Time {
+	if !t.Relative() {
+		return t.Time
+	}
+	return genesisTime.AddDate(t.Years, t.Months, t.Days).Add(t.Duration)
+}
+
+// Lock is a vesting schedule. Without a start the coins are delayed until
+// the end, with a start they vest continuously between start and end.
+type Lock struct {
+	Start *LockTime
+	End   LockTime
+}
+
+var reOffset = regexp.MustCompile(`^\+(\d+)(y|m|w|d|h)$`)
+
+// ParseLock parses a lock schedule:
+//
+//	2020-03-13T23:00:00Z                       delayed until the date
+//	+1y                                        delayed one year after genesis
+//	2019-03-13T23:00:00Z..2021-03-13T23:00:00Z continuous between the dates
+//	+2m..+2y                                   continuous between two offsets
+//
+// Offsets are counted from genesis in years (y), months (m), weeks (w),
+// days (d) or hours (h); "+0" is the genesis time itself.
+func ParseLock(s string) (*Lock, error) {
+	parts := strings.Split(strings.TrimSpace(s), "..")
+	switch len(parts) {
+	case 1:
+		end, err := parseLockTime(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		return &Lock{End: end}, nil
+	case 2:
+		start, err := parseLockTime(parts[0])
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseLockTime(parts[1])
+		if err != nil {
+			return nil, err
+		}
+		return &Lock{Start: &start, End: end}, nil
+	default:
+		return nil, fmt.Errorf("bad lock %q", s)
+	}
+}
+
+func parseLockTime(s string) (LockTime, error) {
+	s = strings.TrimSpace(s)
+	if s == "+0" {
+		return LockTime{}, nil
+	}
+	if !strings.HasPrefix(s, "+") {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return LockTime{}, fmt.Errorf("bad lock time %q: expected RFC3339 or +<n>(y|m|w|d|h)", s)
+		}
+		return LockTime{Time: t}, nil
+	}
+
+	m := reOffset.FindStringSubmatch(s)
+	if m == nil {
+		return LockTime{}, fmt.Errorf("bad lock offset %q: expected +<n>(y|m|w|d|h)", s)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return LockTime{}, fmt.Errorf("bad lock offset %q: %v", s, err)
+	}
+	switch m[2] {
+	case "y":
+		return LockTime{Years: n}, nil
+	case "m":
+		return LockTime{Months: n}, nil
+	case "w":
+		return LockTime{Days: 7 * n}, nil
+	case "d":
+		return LockTime{Days: n}, nil
+	default:
+		return LockTime{Duration: time.Duration(n) * time.Hour}, nil
+	}
+}
+
+// checkVesting validates the lock of an allocation against the genesis time
+func (alloc Allocation) checkVesting(genesisTime time.Time) error {
+	if alloc.Lock == nil {
+		return nil
+	}
+	addr := alloc.Address.String()
+	if !alloc.Vesting.IsPositive() {
+		return ErrVesting{alloc.Position(), addr, "vesting amount must be positive"}
+	}
+	if alloc.Vesting.GT(alloc.Amount) {
+		return ErrVesting{alloc.Position(), addr, fmt.Sprintf("vesting %v exceeds the balance %v", alloc.Vesting, alloc.Amount)}
+	}
+	end := alloc.Lock.End.Resolve(genesisTime)
+	if !end.After(genesisTime) {
+		return ErrVesting{alloc.Position(), addr, fmt.Sprintf("lock ends at %v, not after genesis %v", end, genesisTime)}
+	}
+	if alloc.Lock.Start != nil {
+		start := alloc.Lock.Start.Resolve(genesisTime)
+		if start.Before(genesisTime) {
+			// a zero start time would turn the account into a delayed one
+			return ErrVesting{alloc.Position(), addr, fmt.Sprintf("lock starts at %v, before genesis %v", start, genesisTime)}
+		}
+		if !start.Before(end) {
+			return ErrVesting{alloc.Position(), addr, fmt.Sprintf("lock starts at %v, not before its end %v", start, end)}
+		}
+	}
+	return nil
+}
+
+// genesisAccount turns the allocation into a plain or vesting genesis account
+func (alloc Allocation) genesisAccount(genesisTime time.Time) app.GenesisAccount {
+	acc := app.GenesisAccount{
+		Address: alloc.Address,
+		Coins:   newCoins(alloc.Denom, alloc.Amount),
+	}
+	if alloc.Lock == nil {
+		return acc
+	}
+
+	acc.OriginalVesting = common.ConvertDecCoinsToCoins(newCoins(alloc.Denom, alloc.Vesting))
+	acc.EndTime = alloc.Lock.End.Resolve(genesisTime).Unix()
+	if alloc.Lock.Start != nil {
+		acc.StartTime = alloc.Lock.Start.Resolve(genesisTime).Unix()
+	}
+	return acc
+}
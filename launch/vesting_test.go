@@ -0,0 +1,98 @@
+package launch
+
+import (
+	"testing"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestParseLock(t *testing.T) {
+	genesis := time.Date(2019, 3, 13, 23, 0, 0, 0, time.UTC)
+	date := func(s string) time.Time {
+		d, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	cases := []struct {
+		in    string
+		start string // resolved against genesis, empty for a delayed lock
+		end   string
+		ok    bool
+	}{
+		{"2020-03-13T23:00:00Z", "", "2020-03-13T23:00:00Z", true},
+		{" +1y ", "", "2020-03-13T23:00:00Z", true},
+		{"+2m", "", "2019-05-13T23:00:00Z", true},
+		{"+2w", "", "2019-03-27T23:00:00Z", true},
+		{"+10d", "", "2019-03-23T23:00:00Z", true},
+		{"+36h", "", "2019-03-15T11:00:00Z", true},
+		{"+0", "", "2019-03-13T23:00:00Z", true},
+		{"2019-03-13T23:00:00Z..2021-03-13T23:00:00Z", "2019-03-13T23:00:00Z", "2021-03-13T23:00:00Z", true},
+		{"+2m..+2y", "2019-05-13T23:00:00Z", "2021-03-13T23:00:00Z", true},
+		{"+0..2020-01-01T00:00:00Z", "2019-03-13T23:00:00Z", "2020-01-01T00:00:00Z", true},
+		{"", "", "", false},
+		{"+1", "", "", false},
+		{"+1s", "", "", false},
+		{"+-1y", "", "", false},
+		{"1y", "", "", false},
+		{"2020-03-13", "", "", false},
+		{"+1y..", "", "", false},
+		{"+1y..+2y..+3y", "", "", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.in, func(t *testing.T) {
+			lock, err := ParseLock(tc.in)
+			if tc.ok != (err == nil) {
+				t.Fatalf("ok=%v, got err %v", tc.ok, err)
+			}
+			if !tc.ok {
+				return
+			}
+			if end := lock.End.Resolve(genesis); !end.Equal(date(tc.end)) {
+				t.Errorf("expected end %s, got %v", tc.end, end)
+			}
+			switch {
+			case tc.start == "" && lock.Start != nil:
+				t.Errorf("expected a delayed lock, got start %v", lock.Start.Resolve(genesis))
+			case tc.start != "" && lock.Start == nil:
+				t.Errorf("expected start %s, got a delayed lock", tc.start)
+			case tc.start != "" && !lock.Start.Resolve(genesis).Equal(date(tc.start)):
+				t.Errorf("expected start %s, got %v", tc.start, lock.Start.Resolve(genesis))
+			}
+		})
+	}
+}
+
+func TestCheckVesting(t *testing.T) {
+	genesis := time.Date(2019, 3, 13, 23, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		lock    string
+		vesting int64
+		ok      bool
+	}{
+		{"delayed", "+1y", 50, true},
+		{"continuous", "+0..+1y", 100, true},
+		{"ends at genesis", "+0", 50, false},
+		{"ends before genesis", "2019-01-01T00:00:00Z", 50, false},
+		{"starts before genesis", "2019-01-01T00:00:00Z..+1y", 50, false},
+		{"starts after its end", "+2y..+1y", 50, false},
+		{"exceeds the balance", "+1y", 101, false},
+		{"nothing vesting", "+1y", 0, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lock, err := ParseLock(tc.lock)
+			if err != nil {
+				t.Fatal(err)
+			}
+			alloc := Allocation{Amount: sdk.NewDec(100), Vesting: sdk.NewDec(tc.vesting), Lock: lock}
+			if err := alloc.checkVesting(genesis); tc.ok != (err == nil) {
+				t.Errorf("ok=%v, got err %v", tc.ok, err)
+			}
+		})
+	}
+}
@@ -20,7 +20,7 @@ rm gentx/data/gentx-*
 cp ~/.okdexd/config/gentx/gentx-*.json gentx/data
 
 
-go run main.go
+go run . build
 
 cp ~/.okdexd/config/genesis.json genesis.tmp.json
 
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cosmos/launch/launch"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/go-amino"
+)
+
+const flagAllowUnheld = "allow-unheld"
+
+func restartCmd() *cobra.Command {
+	var cfg launch.RestartConfig
+	var genesisTime, now, output, maintain string
+	cmd := &cobra.Command{
+		Use:   "restart <exported-genesis>",
+		Short: "Build the genesis of a restarted chain from the okchaind export",
+		Long: `Build the genesis of a restarted chain from the genesis exported by okchaind:
+new chain id and genesis time, heights reset to zero, the accounts, staking,
+distribution and gov state carried over, and the module sections the export
+leaves empty taken from the template. The result runs through the same
+validation as a build.
+
+okchaind exports only the accounts, so the validators of the restarted chain
+come from the gentxs of --gentx-dir when the export has no staking validators.
+The bonded tokens are then missing from the accounts: the restart fails when
+the accounts don't hold the whole token supply, unless --allow-unheld accepts
+the loss.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if genesisTime == "" {
+				return fmt.Errorf("--%s is required", flagGenesisTime)
+			}
+			if cfg.GenesisTime, err = time.Parse(time.RFC3339, genesisTime); err != nil {
+				return fmt.Errorf("invalid genesis time %q: %v", genesisTime, err)
+			}
+			if now != "" {
+				t, err := time.Parse(time.RFC3339, now)
+				if err != nil {
+					return fmt.Errorf("invalid %s %q: %v", flagNow, now, err)
+				}
+				if err := launch.CheckGenesisTime(cfg.GenesisTime, t); err != nil {
+					return err
+				}
+			}
+			fmt.Println("chain id    ", cfg.ChainID)
+			fmt.Println("genesis time", cfg.GenesisTime.UTC().Format(time.RFC3339))
+
+			genesisDoc, report, err := launch.Restart(args[0], cfg)
+			if err != nil {
+				return err
+			}
+			if !report.Unheld.IsZero() {
+				fmt.Println("WARNING no account holds", report.Unheld, "of the token supply, allowed by --"+flagAllowUnheld)
+			}
+			fmt.Println("-----------")
+			if err := launch.WriteRestartReport(os.Stdout, report); err != nil {
+				return err
+			}
+			if err := launch.WriteGenesisDoc(amino.NewCodec(), genesisDoc, output); err != nil {
+				return err
+			}
+			return writeMaintainConf(maintain, genesisDoc.GenesisTime)
+		},
+	}
+	cmd.Flags().StringVar(&cfg.ChainID, flagChainID, "", "chain id of the restarted network, it must differ from the exported one")
+	cmd.Flags().StringVar(&genesisTime, flagGenesisTime, "", "genesis time of the restarted network, RFC3339")
+	cmd.Flags().StringVar(&now, flagNow, "", "check the genesis time is after this time (RFC3339)")
+	cmd.Flags().StringVar(&cfg.Template, flagTemplate, defaultGenesisTemplate, "genesis template for the module sections the export leaves empty")
+	cmd.Flags().StringVar(&cfg.GenTxPath, flagGenTxDir, "", "directory holding the gentxs of the new validators, when the export has none")
+	cmd.Flags().BoolVar(&cfg.AllowUnheld, flagAllowUnheld, false, "accept a token supply the accounts don't hold, the bonded tokens of an export without staking state")
+	cmd.Flags().StringVarP(&output, flagOutput, "o", defaultGenesisFile, "where to write the genesis file")
+	cmd.Flags().StringVar(&maintain, flagMaintain, defaultMaintainFile, "where to write the backend maintain.conf")
+	return cmd
+}
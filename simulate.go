@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cosmos/launch/launch"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/go-amino"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+const flagBlocks = "blocks"
+
+func simulateCmd() *cobra.Command {
+	var cfg launchConfig
+	var blocks int
+	cmd := &cobra.Command{
+		Use:   "simulate [genesis-file]",
+		Short: "Boot the genesis on an in-memory app and run a few empty blocks",
+		Long: `Boot the genesis on an in-memory app and run a few empty blocks.
+Without a genesis file the genesis is generated from the inputs, like validate does.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var genesisDoc *tmtypes.GenesisDoc
+			if len(args) == 1 {
+				doc, _, err := launch.LoadGenesis(amino.NewCodec(), args[0])
+				if err != nil {
+					return err
+				}
+				genesisDoc = doc
+			} else {
+				_, doc, err := generateGenesis(cfg)
+				if err != nil {
+					return err
+				}
+				genesisDoc = doc
+			}
+
+			fmt.Println("-----------")
+			sim, err := launch.Simulate(genesisDoc, blocks)
+			if err != nil {
+				if e, ok := err.(launch.ErrSimulationPanic); ok {
+					os.Stderr.Write(e.Stack)
+				}
+				return err
+			}
+			if err := launch.WriteSimulation(os.Stdout, sim); err != nil {
+				return err
+			}
+			if err := sim.CheckPowers(); err != nil {
+				return err
+			}
+			fmt.Println("genesis boots")
+			return nil
+		},
+	}
+	addBuildFlags(cmd, &cfg)
+	cmd.Flags().IntVar(&blocks, flagBlocks, 5, "number of empty blocks to run after InitChain")
+	return cmd
+}
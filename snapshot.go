@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/launch/launch"
+	"github.com/spf13/cobra"
+)
+
+const (
+	flagRatio      = "ratio"
+	flagMin        = "min"
+	flagDenom      = "denom"
+	flagDecimals   = "decimals"
+	flagDustReport = "dust-report"
+)
+
+func snapshotCmd() *cobra.Command {
+	var ratio, min, output, dustReport string
+	var denoms []string
+	var decimals int
+	cmd := &cobra.Command{
+		Use:   "snapshot <exported-genesis>",
+		Short: "Turn the balances of an exported chain into an allocation file",
+		Long: `Read the liquid, bonded and unbonding balances of every holder of an exported
+genesis (okchaind export, or any cosmos-sdk one with app_state.auth.accounts),
+multiply them by --ratio and write them as an allocation file for a manifest
+source: csv, or an object file when the extension is .json and there is a
+single denom. Holders below --min, or left with nothing after the ratio, are
+dropped as dust and counted in the summary. Module accounts (staking pools,
+distribution, gov) are left out, the stake they hold is counted for its
+delegators.
+
+Amounts are read in units of --decimals decimals, --denom maps an exported
+denom to the allocated one (uatom=okb), only the mapped denoms are kept once
+one is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg := launch.SnapshotConfig{Decimals: decimals, Denoms: make(map[string]string)}
+			var err error
+			if cfg.Ratio, err = launch.ParseAmount(json.Number(ratio), sdk.Precision); err != nil || !cfg.Ratio.IsPositive() {
+				return fmt.Errorf("bad --%s %q, expected a positive decimal", flagRatio, ratio)
+			}
+			if cfg.Threshold, err = launch.ParseAmount(json.Number(min), sdk.Precision); err != nil || cfg.Threshold.IsNegative() {
+				return fmt.Errorf("bad --%s %q, expected a decimal amount", flagMin, min)
+			}
+			for _, mapping := range denoms {
+				parts := strings.Split(mapping, "=")
+				if len(parts) != 2 || parts[0] == "" {
+					return fmt.Errorf("bad --%s %q, expected <exported>=<allocated>", flagDenom, mapping)
+				}
+				cfg.Denoms[parts[0]] = parts[1]
+			}
+			if decimals < 0 || decimals > 18 {
+				return fmt.Errorf("bad --%s %d, expected 0 to 18", flagDecimals, decimals)
+			}
+
+			snapshot, err := launch.TakeSnapshot(args[0], cfg)
+			if err != nil {
+				return err
+			}
+			if !snapshot.Staking {
+				fmt.Println("WARNING the export has no staking state, only the liquid balances are counted")
+			}
+			if len(snapshot.Modules) > 0 {
+				fmt.Println(len(snapshot.Modules), "module account(s) left out:", strings.Join(snapshot.Modules, ", "))
+			}
+			if len(snapshot.Holders) == 0 {
+				return fmt.Errorf("no holder left above --%s %s", flagMin, min)
+			}
+
+			var buf bytes.Buffer
+			if filepath.Ext(output) == ".json" {
+				err = launch.WriteSnapshotObject(&buf, snapshot)
+			} else {
+				err = launch.WriteSnapshotCSV(&buf, snapshot)
+			}
+			if err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(output, buf.Bytes(), 0644); err != nil {
+				return err
+			}
+			if dustReport != "" {
+				if err := writeReport(dustReport, launch.DustReport(snapshot)); err != nil {
+					return err
+				}
+			}
+
+			fmt.Println("exported chain", snapshot.ChainID)
+			fmt.Println("-----------")
+			if err := launch.WriteTables(os.Stdout, launch.SnapshotReport(snapshot), launch.FormatText); err != nil {
+				return err
+			}
+			fmt.Println("-----------")
+			fmt.Println(len(snapshot.Holders), "holder(s) written to", output, "and", len(snapshot.Dust), "dropped as dust")
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&ratio, flagRatio, "1", "new tokens per exported token")
+	cmd.Flags().StringVar(&min, flagMin, "0", "smallest allocation kept, after the ratio")
+	cmd.Flags().StringSliceVar(&denoms, flagDenom, nil, "exported denom mapped to the allocated one, e.g. uatom=okb")
+	cmd.Flags().IntVar(&decimals, flagDecimals, sdk.Precision, "decimals of the exported amounts")
+	cmd.Flags().StringVarP(&output, flagOutput, "o", defaultSnapshotFile, "allocation file to write, csv or .json")
+	cmd.Flags().StringVar(&dustReport, flagDustReport, "", "where to list the dust holders, as csv, markdown (.md) or text")
+	return cmd
+}